@@ -20,15 +20,103 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	PushTokenScopes = "PushToken.Scopes"
+)
+
 // Defines values for PingResponseStatus.
 const (
 	Ok PingResponseStatus = "ok"
 )
 
+// Defines values for Format.
+const (
+	Json Format = "json"
+	Png  Format = "png"
+	Svg  Format = "svg"
+)
+
+// Defines values for Style.
+const (
+	Flat        Style = "flat"
+	FlatSquare  Style = "flat-square"
+	ForTheBadge Style = "for-the-badge"
+	Plastic     Style = "plastic"
+	Social      Style = "social"
+)
+
 // error object for client errors
 type ClientError struct {
 	Description string `json:"description"`
-	Error       string `json:"error"`
+
+	// Each problem found with the request, if there are several
+	Details *[]string `json:"details,omitempty"`
+	Error   string    `json:"error"`
+}
+
+// Coverage computed from an uploaded coverage report.
+type CoverageResult struct {
+	Branch string `json:"branch"`
+
+	// Number of covered lines.
+	Covered int64 `json:"covered"`
+
+	// Change in percentage from the previous upload to the branch, if any.
+	Delta *float64 `json:"delta,omitempty"`
+
+	// Number of lines which can be covered.
+	Lines int64 `json:"lines"`
+
+	// Percentage of lines covered.
+	Percent float64 `json:"percent"`
+	Project string  `json:"project"`
+}
+
+// The text and colors of a badge, in the shields.io endpoint schema so it can be used as the target of an
+// endpoint badge.
+type EndpointBadge struct {
+	// Seconds the badge may be cached for
+	CacheSeconds *int `json:"cacheSeconds,omitempty"`
+
+	// CSS color of the message. Multi-segment badges use the color of the first segment.
+	Color string `json:"color"`
+
+	// Set if the badge reports a failure
+	IsError bool   `json:"isError"`
+	Label   string `json:"label"`
+
+	// CSS color of the label
+	LabelColor string `json:"labelColor"`
+
+	// Message of the badge. The segments of multi-segment badges are joined with " | ".
+	Message string `json:"message"`
+
+	// Version of the endpoint schema, always 1
+	SchemaVersion int `json:"schemaVersion"`
+}
+
+// Parameter description
+type ParameterDesc struct {
+	// Value used if the request doesn't set the parameter
+	Default *string `json:"default,omitempty"`
+
+	// Description of the parameter
+	Description *string `json:"description,omitempty"`
+
+	// Name of the parameter
+	Name *string `json:"name,omitempty"`
+
+	// Regular expression values must match
+	Pattern *string `json:"pattern,omitempty"`
+
+	// Requests must set the parameter
+	Required *bool `json:"required,omitempty"`
+
+	// Type of the parameter (string, int, enum or bool)
+	Type *string `json:"type,omitempty"`
+
+	// Values accepted by enum parameters
+	Values *[]string `json:"values,omitempty"`
 }
 
 // API availability response endpoint
@@ -41,6 +129,139 @@ type PingResponse struct {
 // PingResponseStatus defines model for PingResponse.Status.
 type PingResponseStatus string
 
+// Definition of a predefined badge
+type PredefinedBadgeDesc struct {
+	// Description of what the badge does
+	Description *string                   `json:"description,omitempty"`
+	Examples    *[]PredefinedBadgeExample `json:"examples,omitempty"`
+
+	// Name of the badge
+	Name       string           `json:"name"`
+	Parameters *[]ParameterDesc `json:"parameters,omitempty"`
+}
+
+// Example usage of a predefined badge
+type PredefinedBadgeExample struct {
+	// Description of the example
+	Description *string `json:"description,omitempty"`
+
+	// Query parameters used by the example
+	Parameters *PredefinedBadgeExample_Parameters `json:"parameters,omitempty"`
+
+	// Ready-to-use URL which renders the example badge
+	Url *string `json:"url,omitempty"`
+}
+
+// Query parameters used by the example
+type PredefinedBadgeExample_Parameters struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
+// Summary of an uploaded test report.
+type TestResults struct {
+	Branch string `json:"branch"`
+
+	// Commit the report was generated from, if given.
+	Commit  *string `json:"commit,omitempty"`
+	Failed  int64   `json:"failed"`
+	Passed  int64   `json:"passed"`
+	Project string  `json:"project"`
+	Skipped int64   `json:"skipped"`
+	Total   int64   `json:"total"`
+
+	// When the report was uploaded.
+	Updated *time.Time `json:"updated,omitempty"`
+}
+
+// ErrorBadge defines model for ErrorBadge.
+type ErrorBadge = bool
+
+// Format defines model for Format.
+type Format string
+
+// Logo defines model for Logo.
+type Logo = string
+
+// LogoColor defines model for LogoColor.
+type LogoColor = string
+
+// LogoWidth defines model for LogoWidth.
+type LogoWidth = int
+
+// Scale defines model for Scale.
+type Scale = float64
+
+// Style defines model for Style.
+type Style string
+
+// GetBadgeCoverageProjectParams defines parameters for GetBadgeCoverageProject.
+type GetBadgeCoverageProjectParams struct {
+	// Branch the coverage belongs to. Defaults to the server default branch.
+	Branch *string `form:"branch,omitempty" json:"branch,omitempty"`
+
+	// Pongo2 format string to display for the badge label. Defaults to `coverage`.
+	Label *string `form:"label,omitempty" json:"label,omitempty"`
+
+	// Pongo2 format string to display for the badge message. Defaults to `{{ r.coverage }}`.
+	Message *string `form:"message,omitempty" json:"message,omitempty"`
+
+	// Pongo2 format string to select a badge color by. Defaults to a color for the coverage.
+	Color *string `form:"color,omitempty" json:"color,omitempty"`
+
+	// Pongo2 format string to select the label color by.
+	LabelColor *string `form:"labelColor,omitempty" json:"labelColor,omitempty"`
+
+	// Show the change in coverage from the previous upload as a second segment.
+	Delta *bool `form:"delta,omitempty" json:"delta,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgeCoverageProjectParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgeCoverageProjectParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
+}
+
+// GetBadgeCoverageProjectParamsStyle defines parameters for GetBadgeCoverageProject.
+type GetBadgeCoverageProjectParamsStyle string
+
+// GetBadgeCoverageProjectParamsFormat defines parameters for GetBadgeCoverageProject.
+type GetBadgeCoverageProjectParamsFormat string
+
+// PostBadgeCoverageProjectParams defines parameters for PostBadgeCoverageProject.
+type PostBadgeCoverageProjectParams struct {
+	// Branch the coverage belongs to. Defaults to the server default branch.
+	Branch *string `form:"branch,omitempty" json:"branch,omitempty"`
+
+	// Format of the report. Detected from the report if not set.
+	ReportFormat *PostBadgeCoverageProjectParamsReportFormat `form:"reportFormat,omitempty" json:"reportFormat,omitempty"`
+
+	// Commit the report was generated from, kept in the history of the branch.
+	Commit *string `form:"commit,omitempty" json:"commit,omitempty"`
+}
+
+// PostBadgeCoverageProjectParamsReportFormat defines parameters for PostBadgeCoverageProject.
+type PostBadgeCoverageProjectParamsReportFormat string
+
 // GetBadgeDynamicParams defines parameters for GetBadgeDynamic.
 type GetBadgeDynamicParams struct {
 	// URL of the server to fetch dynamic data from.
@@ -54,8 +275,54 @@ type GetBadgeDynamicParams struct {
 
 	// Pongo2 format string to select a badge color by
 	Color *string `form:"color,omitempty" json:"color,omitempty"`
+
+	// Pongo2 format string to select the label color by
+	LabelColor *string `form:"labelColor,omitempty" json:"labelColor,omitempty"`
+
+	// Data format of the target response. `auto` selects the format from the response Content-Type, treating
+	// unrecognized content types as JSON.
+	TargetFormat *GetBadgeDynamicParamsTargetFormat `form:"targetFormat,omitempty" json:"targetFormat,omitempty"`
+
+	// Query expression evaluated against the decoded target response. The result is available to the templates
+	// as `q`. Expressions starting with `$` are JSONPath, otherwise they are JMESPath.
+	Query *string `form:"query,omitempty" json:"query,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgeDynamicParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgeDynamicParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
 }
 
+// GetBadgeDynamicParamsTargetFormat defines parameters for GetBadgeDynamic.
+type GetBadgeDynamicParamsTargetFormat string
+
+// GetBadgeDynamicParamsStyle defines parameters for GetBadgeDynamic.
+type GetBadgeDynamicParamsStyle string
+
+// GetBadgeDynamicParamsFormat defines parameters for GetBadgeDynamic.
+type GetBadgeDynamicParamsFormat string
+
 // GetBadgePredefinedPredefinedNameParams_Params defines parameters for GetBadgePredefinedPredefinedName.
 type GetBadgePredefinedPredefinedNameParams_Params struct {
 	AdditionalProperties map[string]interface{} `json:"-"`
@@ -63,10 +330,140 @@ type GetBadgePredefinedPredefinedNameParams_Params struct {
 
 // GetBadgePredefinedPredefinedNameParams defines parameters for GetBadgePredefinedPredefinedName.
 type GetBadgePredefinedPredefinedNameParams struct {
-	// Predefined badges may define custom parameters to control templating.
+	// Predefined badges may define custom parameters to control templating. Parameters are checked against their
+	// definitions, and every missing or invalid parameter is listed in the details of the error.
 	Params *GetBadgePredefinedPredefinedNameParams_Params `form:"params,omitempty" json:"params,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgePredefinedPredefinedNameParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgePredefinedPredefinedNameParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
+}
+
+// GetBadgePredefinedPredefinedNameParamsStyle defines parameters for GetBadgePredefinedPredefinedName.
+type GetBadgePredefinedPredefinedNameParamsStyle string
+
+// GetBadgePredefinedPredefinedNameParamsFormat defines parameters for GetBadgePredefinedPredefinedName.
+type GetBadgePredefinedPredefinedNameParamsFormat string
+
+// GetBadgePushedNamespaceNameParams defines parameters for GetBadgePushedNamespaceName.
+type GetBadgePushedNamespaceNameParams struct {
+	// Pongo2 format string to display for the badge label. Defaults to `{{ r.label }}`.
+	Label *string `form:"label,omitempty" json:"label,omitempty"`
+
+	// Pongo2 format string to display for the badge message. Defaults to `{{ r.message }}`.
+	Message *string `form:"message,omitempty" json:"message,omitempty"`
+
+	// Pongo2 format string to select a badge color by. Defaults to `{{ r.color }}`.
+	Color *string `form:"color,omitempty" json:"color,omitempty"`
+
+	// Pongo2 format string to select the label color by. Defaults to `{{ r.labelColor }}`.
+	LabelColor *string `form:"labelColor,omitempty" json:"labelColor,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgePushedNamespaceNameParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgePushedNamespaceNameParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
+}
+
+// GetBadgePushedNamespaceNameParamsStyle defines parameters for GetBadgePushedNamespaceName.
+type GetBadgePushedNamespaceNameParamsStyle string
+
+// GetBadgePushedNamespaceNameParamsFormat defines parameters for GetBadgePushedNamespaceName.
+type GetBadgePushedNamespaceNameParamsFormat string
+
+// PutBadgePushedNamespaceNameJSONBody defines parameters for PutBadgePushedNamespaceName.
+type PutBadgePushedNamespaceNameJSONBody = interface{}
+
+// GetBadgeSegmentsParams defines parameters for GetBadgeSegments.
+type GetBadgeSegmentsParams struct {
+	// Pongo2 format string to display for the badge label
+	Label *string `form:"label,omitempty" json:"label,omitempty"`
+
+	// Pongo2 format string to select the label color by
+	LabelColor *string `form:"labelColor,omitempty" json:"labelColor,omitempty"`
+
+	// Pongo2 format strings to display in each segment
+	Segment []string `form:"segment" json:"segment"`
+
+	// Pongo2 format strings to select the color of each segment by
+	Color *[]string `form:"color,omitempty" json:"color,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgeSegmentsParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgeSegmentsParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
 }
 
+// GetBadgeSegmentsParamsStyle defines parameters for GetBadgeSegments.
+type GetBadgeSegmentsParamsStyle string
+
+// GetBadgeSegmentsParamsFormat defines parameters for GetBadgeSegments.
+type GetBadgeSegmentsParamsFormat string
+
 // GetBadgeStaticParams defines parameters for GetBadgeStatic.
 type GetBadgeStaticParams struct {
 	// Pongo2 format string to display for fo the badge label
@@ -77,8 +474,111 @@ type GetBadgeStaticParams struct {
 
 	// Pongo2 format string to select a badge color by
 	Color *string `form:"color,omitempty" json:"color,omitempty"`
+
+	// Pongo2 format string to select the label color by
+	LabelColor *string `form:"labelColor,omitempty" json:"labelColor,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgeStaticParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgeStaticParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
+}
+
+// GetBadgeStaticParamsStyle defines parameters for GetBadgeStatic.
+type GetBadgeStaticParamsStyle string
+
+// GetBadgeStaticParamsFormat defines parameters for GetBadgeStatic.
+type GetBadgeStaticParamsFormat string
+
+// GetBadgeTestsProjectParams defines parameters for GetBadgeTestsProject.
+type GetBadgeTestsProjectParams struct {
+	// Branch the tests belong to. Defaults to the server default branch.
+	Branch *string `form:"branch,omitempty" json:"branch,omitempty"`
+
+	// Pongo2 format string to display for the badge label. Defaults to `tests`.
+	Label *string `form:"label,omitempty" json:"label,omitempty"`
+
+	// Pongo2 format string to display for the badge message. Defaults to `{{ r.summary }}`.
+	Message *string `form:"message,omitempty" json:"message,omitempty"`
+
+	// Pongo2 format string to select a badge color by. Defaults to a color for the results.
+	Color *string `form:"color,omitempty" json:"color,omitempty"`
+
+	// Pongo2 format string to select the label color by.
+	LabelColor *string `form:"labelColor,omitempty" json:"labelColor,omitempty"`
+
+	// Badge style. The server default style is used if not set. Predefined badges use their configured style
+	// unless this is set.
+	Style *GetBadgeTestsProjectParamsStyle `form:"style,omitempty" json:"style,omitempty"`
+
+	// Logo shown before the label. Either the name of an embedded icon or an icon in the server icon directory, or
+	// an image data URI (svg+xml, png, jpeg or gif).
+	Logo *Logo `form:"logo,omitempty" json:"logo,omitempty"`
+
+	// Color of SVG logos. Defaults to the text color of the badge style.
+	LogoColor *LogoColor `form:"logoColor,omitempty" json:"logoColor,omitempty"`
+
+	// Width of the logo in pixels.
+	LogoWidth *LogoWidth `form:"logoWidth,omitempty" json:"logoWidth,omitempty"`
+
+	// Format of the badge. PNG badges can also be requested by adding a `.png` suffix to the path or with an
+	// `Accept: image/png` header. `json` returns the label, message and colors of the badge in the shields.io
+	// endpoint schema, and can also be requested with an `Accept: application/json` header.
+	Format *GetBadgeTestsProjectParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Scale factor of PNG badges, e.g. 2 for HiDPI displays.
+	Scale *Scale `form:"scale,omitempty" json:"scale,omitempty"`
+
+	// Render failures as an error badge instead of a JSON error. Clients which accept application/json always
+	// receive JSON errors.
+	ErrorBadge *ErrorBadge `form:"errorBadge,omitempty" json:"errorBadge,omitempty"`
+}
+
+// GetBadgeTestsProjectParamsStyle defines parameters for GetBadgeTestsProject.
+type GetBadgeTestsProjectParamsStyle string
+
+// GetBadgeTestsProjectParamsFormat defines parameters for GetBadgeTestsProject.
+type GetBadgeTestsProjectParamsFormat string
+
+// PostBadgeTestsProjectParams defines parameters for PostBadgeTestsProject.
+type PostBadgeTestsProjectParams struct {
+	// Branch the tests belong to. Defaults to the server default branch.
+	Branch *string `form:"branch,omitempty" json:"branch,omitempty"`
+
+	// Format of the report. Detected from the report if not set.
+	ReportFormat *PostBadgeTestsProjectParamsReportFormat `form:"reportFormat,omitempty" json:"reportFormat,omitempty"`
+
+	// Commit the report was generated from.
+	Commit *string `form:"commit,omitempty" json:"commit,omitempty"`
 }
 
+// PostBadgeTestsProjectParamsReportFormat defines parameters for PostBadgeTestsProject.
+type PostBadgeTestsProjectParamsReportFormat string
+
+// PutBadgePushedNamespaceNameJSONRequestBody defines body for PutBadgePushedNamespaceName for application/json ContentType.
+type PutBadgePushedNamespaceNameJSONRequestBody = PutBadgePushedNamespaceNameJSONBody
+
 // Getter for additional properties for GetBadgePredefinedPredefinedNameParams_Params. Returns the specified
 // element and whether it was found
 func (a GetBadgePredefinedPredefinedNameParams_Params) Get(fieldName string) (value interface{}, found bool) {
@@ -132,9 +632,68 @@ func (a GetBadgePredefinedPredefinedNameParams_Params) MarshalJSON() ([]byte, er
 	return json.Marshal(object)
 }
 
+// Getter for additional properties for PredefinedBadgeExample_Parameters. Returns the specified
+// element and whether it was found
+func (a PredefinedBadgeExample_Parameters) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for PredefinedBadgeExample_Parameters
+func (a *PredefinedBadgeExample_Parameters) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for PredefinedBadgeExample_Parameters to handle AdditionalProperties
+func (a *PredefinedBadgeExample_Parameters) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for PredefinedBadgeExample_Parameters to handle AdditionalProperties
+func (a PredefinedBadgeExample_Parameters) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /badge/coverage/{project})
+	GetBadgeCoverageProject(ctx echo.Context, project string, params GetBadgeCoverageProjectParams) error
+
+	// (POST /badge/coverage/{project})
+	PostBadgeCoverageProject(ctx echo.Context, project string, params PostBadgeCoverageProjectParams) error
+
 	// (GET /badge/dynamic)
 	GetBadgeDynamic(ctx echo.Context, params GetBadgeDynamicParams) error
 
@@ -144,9 +703,24 @@ type ServerInterface interface {
 	// (GET /badge/predefined/{predefined_name}/)
 	GetBadgePredefinedPredefinedName(ctx echo.Context, predefinedName string, params GetBadgePredefinedPredefinedNameParams) error
 
+	// (GET /badge/pushed/{namespace}/{name})
+	GetBadgePushedNamespaceName(ctx echo.Context, namespace string, name string, params GetBadgePushedNamespaceNameParams) error
+
+	// (PUT /badge/pushed/{namespace}/{name})
+	PutBadgePushedNamespaceName(ctx echo.Context, namespace string, name string) error
+
+	// (GET /badge/segments)
+	GetBadgeSegments(ctx echo.Context, params GetBadgeSegmentsParams) error
+
 	// (GET /badge/static)
 	GetBadgeStatic(ctx echo.Context, params GetBadgeStaticParams) error
 
+	// (GET /badge/tests/{project})
+	GetBadgeTestsProject(ctx echo.Context, project string, params GetBadgeTestsProjectParams) error
+
+	// (POST /badge/tests/{project})
+	PostBadgeTestsProject(ctx echo.Context, project string, params PostBadgeTestsProjectParams) error
+
 	// (GET /openapi.yaml)
 	GetOpenapiYaml(ctx echo.Context) error
 
@@ -159,17 +733,24 @@ type ServerInterfaceWrapper struct {
 	Handler ServerInterface
 }
 
-// GetBadgeDynamic converts echo context to params.
-func (w *ServerInterfaceWrapper) GetBadgeDynamic(ctx echo.Context) error {
+// GetBadgeCoverageProject converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgeCoverageProject(ctx echo.Context) error {
 	var err error
+	// ------------- Path parameter "project" -------------
+	var project string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "project", runtime.ParamLocationPath, ctx.Param("project"), &project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetBadgeDynamicParams
-	// ------------- Required query parameter "target" -------------
+	var params GetBadgeCoverageProjectParams
+	// ------------- Optional query parameter "branch" -------------
 
-	err = runtime.BindQueryParameter("form", true, true, "target", ctx.QueryParams(), &params.Target)
+	err = runtime.BindQueryParameter("form", true, false, "branch", ctx.QueryParams(), &params.Branch)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter target: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter branch: %s", err))
 	}
 
 	// ------------- Optional query parameter "label" -------------
@@ -193,47 +774,526 @@ func (w *ServerInterfaceWrapper) GetBadgeDynamic(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
 	}
 
-	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetBadgeDynamic(ctx, params)
-	return err
-}
+	// ------------- Optional query parameter "labelColor" -------------
 
-// GetBadgePredefined converts echo context to params.
-func (w *ServerInterfaceWrapper) GetBadgePredefined(ctx echo.Context) error {
-	var err error
+	err = runtime.BindQueryParameter("form", true, false, "labelColor", ctx.QueryParams(), &params.LabelColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter labelColor: %s", err))
+	}
 
-	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetBadgePredefined(ctx)
-	return err
-}
+	// ------------- Optional query parameter "delta" -------------
 
-// GetBadgePredefinedPredefinedName converts echo context to params.
-func (w *ServerInterfaceWrapper) GetBadgePredefinedPredefinedName(ctx echo.Context) error {
-	var err error
-	// ------------- Path parameter "predefined_name" -------------
-	var predefinedName string
+	err = runtime.BindQueryParameter("form", true, false, "delta", ctx.QueryParams(), &params.Delta)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter delta: %s", err))
+	}
 
-	err = runtime.BindStyledParameterWithLocation("simple", false, "predefined_name", runtime.ParamLocationPath, ctx.Param("predefined_name"), &predefinedName)
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter predefined_name: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetBadgePredefinedPredefinedNameParams
-	// ------------- Optional query parameter "params" -------------
+	// ------------- Optional query parameter "logo" -------------
 
-	err = runtime.BindQueryParameter("form", true, false, "params", ctx.QueryParams(), &params.Params)
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter params: %s", err))
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
 	}
 
-	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetBadgePredefinedPredefinedName(ctx, predefinedName, params)
-	return err
-}
+	// ------------- Optional query parameter "logoColor" -------------
 
-// GetBadgeStatic converts echo context to params.
-func (w *ServerInterfaceWrapper) GetBadgeStatic(ctx echo.Context) error {
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgeCoverageProject(ctx, project, params)
+	return err
+}
+
+// PostBadgeCoverageProject converts echo context to params.
+func (w *ServerInterfaceWrapper) PostBadgeCoverageProject(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "project" -------------
+	var project string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "project", runtime.ParamLocationPath, ctx.Param("project"), &project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
+
+	ctx.Set(PushTokenScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBadgeCoverageProjectParams
+	// ------------- Optional query parameter "branch" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "branch", ctx.QueryParams(), &params.Branch)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter branch: %s", err))
+	}
+
+	// ------------- Optional query parameter "reportFormat" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reportFormat", ctx.QueryParams(), &params.ReportFormat)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter reportFormat: %s", err))
+	}
+
+	// ------------- Optional query parameter "commit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "commit", ctx.QueryParams(), &params.Commit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter commit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostBadgeCoverageProject(ctx, project, params)
+	return err
+}
+
+// GetBadgeDynamic converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgeDynamic(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBadgeDynamicParams
+	// ------------- Required query parameter "target" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "target", ctx.QueryParams(), &params.Target)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter target: %s", err))
+	}
+
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// ------------- Optional query parameter "message" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "message", ctx.QueryParams(), &params.Message)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter message: %s", err))
+	}
+
+	// ------------- Optional query parameter "color" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "color", ctx.QueryParams(), &params.Color)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
+	}
+
+	// ------------- Optional query parameter "labelColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "labelColor", ctx.QueryParams(), &params.LabelColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter labelColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "targetFormat" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "targetFormat", ctx.QueryParams(), &params.TargetFormat)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter targetFormat: %s", err))
+	}
+
+	// ------------- Optional query parameter "query" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "query", ctx.QueryParams(), &params.Query)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter query: %s", err))
+	}
+
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
+	}
+
+	// ------------- Optional query parameter "logo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgeDynamic(ctx, params)
+	return err
+}
+
+// GetBadgePredefined converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgePredefined(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgePredefined(ctx)
+	return err
+}
+
+// GetBadgePredefinedPredefinedName converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgePredefinedPredefinedName(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "predefined_name" -------------
+	var predefinedName string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "predefined_name", runtime.ParamLocationPath, ctx.Param("predefined_name"), &predefinedName)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter predefined_name: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBadgePredefinedPredefinedNameParams
+	// ------------- Optional query parameter "params" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "params", ctx.QueryParams(), &params.Params)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter params: %s", err))
+	}
+
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
+	}
+
+	// ------------- Optional query parameter "logo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgePredefinedPredefinedName(ctx, predefinedName, params)
+	return err
+}
+
+// GetBadgePushedNamespaceName converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgePushedNamespaceName(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "namespace" -------------
+	var namespace string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "namespace", runtime.ParamLocationPath, ctx.Param("namespace"), &namespace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter namespace: %s", err))
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBadgePushedNamespaceNameParams
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// ------------- Optional query parameter "message" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "message", ctx.QueryParams(), &params.Message)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter message: %s", err))
+	}
+
+	// ------------- Optional query parameter "color" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "color", ctx.QueryParams(), &params.Color)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
+	}
+
+	// ------------- Optional query parameter "labelColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "labelColor", ctx.QueryParams(), &params.LabelColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter labelColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
+	}
+
+	// ------------- Optional query parameter "logo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgePushedNamespaceName(ctx, namespace, name, params)
+	return err
+}
+
+// PutBadgePushedNamespaceName converts echo context to params.
+func (w *ServerInterfaceWrapper) PutBadgePushedNamespaceName(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "namespace" -------------
+	var namespace string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "namespace", runtime.ParamLocationPath, ctx.Param("namespace"), &namespace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter namespace: %s", err))
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "name", runtime.ParamLocationPath, ctx.Param("name"), &name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	ctx.Set(PushTokenScopes, []string{""})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PutBadgePushedNamespaceName(ctx, namespace, name)
+	return err
+}
+
+// GetBadgeSegments converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgeSegments(ctx echo.Context) error {
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBadgeSegmentsParams
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// ------------- Optional query parameter "labelColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "labelColor", ctx.QueryParams(), &params.LabelColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter labelColor: %s", err))
+	}
+
+	// ------------- Required query parameter "segment" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "segment", ctx.QueryParams(), &params.Segment)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter segment: %s", err))
+	}
+
+	// ------------- Optional query parameter "color" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "color", ctx.QueryParams(), &params.Color)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
+	}
+
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
+	}
+
+	// ------------- Optional query parameter "logo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgeSegments(ctx, params)
+	return err
+}
+
+// GetBadgeStatic converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgeStatic(ctx echo.Context) error {
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
@@ -259,11 +1319,210 @@ func (w *ServerInterfaceWrapper) GetBadgeStatic(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
 	}
 
+	// ------------- Optional query parameter "labelColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "labelColor", ctx.QueryParams(), &params.LabelColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter labelColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
+	}
+
+	// ------------- Optional query parameter "logo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.GetBadgeStatic(ctx, params)
 	return err
 }
 
+// GetBadgeTestsProject converts echo context to params.
+func (w *ServerInterfaceWrapper) GetBadgeTestsProject(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "project" -------------
+	var project string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "project", runtime.ParamLocationPath, ctx.Param("project"), &project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBadgeTestsProjectParams
+	// ------------- Optional query parameter "branch" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "branch", ctx.QueryParams(), &params.Branch)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter branch: %s", err))
+	}
+
+	// ------------- Optional query parameter "label" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "label", ctx.QueryParams(), &params.Label)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter label: %s", err))
+	}
+
+	// ------------- Optional query parameter "message" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "message", ctx.QueryParams(), &params.Message)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter message: %s", err))
+	}
+
+	// ------------- Optional query parameter "color" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "color", ctx.QueryParams(), &params.Color)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter color: %s", err))
+	}
+
+	// ------------- Optional query parameter "labelColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "labelColor", ctx.QueryParams(), &params.LabelColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter labelColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "style" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "style", ctx.QueryParams(), &params.Style)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter style: %s", err))
+	}
+
+	// ------------- Optional query parameter "logo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logo", ctx.QueryParams(), &params.Logo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logo: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoColor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoColor", ctx.QueryParams(), &params.LogoColor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoColor: %s", err))
+	}
+
+	// ------------- Optional query parameter "logoWidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "logoWidth", ctx.QueryParams(), &params.LogoWidth)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter logoWidth: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "scale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "scale", ctx.QueryParams(), &params.Scale)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter scale: %s", err))
+	}
+
+	// ------------- Optional query parameter "errorBadge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "errorBadge", ctx.QueryParams(), &params.ErrorBadge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter errorBadge: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetBadgeTestsProject(ctx, project, params)
+	return err
+}
+
+// PostBadgeTestsProject converts echo context to params.
+func (w *ServerInterfaceWrapper) PostBadgeTestsProject(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "project" -------------
+	var project string
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "project", runtime.ParamLocationPath, ctx.Param("project"), &project)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter project: %s", err))
+	}
+
+	ctx.Set(PushTokenScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBadgeTestsProjectParams
+	// ------------- Optional query parameter "branch" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "branch", ctx.QueryParams(), &params.Branch)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter branch: %s", err))
+	}
+
+	// ------------- Optional query parameter "reportFormat" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reportFormat", ctx.QueryParams(), &params.ReportFormat)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter reportFormat: %s", err))
+	}
+
+	// ------------- Optional query parameter "commit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "commit", ctx.QueryParams(), &params.Commit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter commit: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.PostBadgeTestsProject(ctx, project, params)
+	return err
+}
+
 // GetOpenapiYaml converts echo context to params.
 func (w *ServerInterfaceWrapper) GetOpenapiYaml(ctx echo.Context) error {
 	var err error
@@ -310,10 +1569,17 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 		Handler: si,
 	}
 
+	router.GET(baseURL+"/badge/coverage/:project", wrapper.GetBadgeCoverageProject)
+	router.POST(baseURL+"/badge/coverage/:project", wrapper.PostBadgeCoverageProject)
 	router.GET(baseURL+"/badge/dynamic", wrapper.GetBadgeDynamic)
 	router.GET(baseURL+"/badge/predefined", wrapper.GetBadgePredefined)
 	router.GET(baseURL+"/badge/predefined/:predefined_name/", wrapper.GetBadgePredefinedPredefinedName)
+	router.GET(baseURL+"/badge/pushed/:namespace/:name", wrapper.GetBadgePushedNamespaceName)
+	router.PUT(baseURL+"/badge/pushed/:namespace/:name", wrapper.PutBadgePushedNamespaceName)
+	router.GET(baseURL+"/badge/segments", wrapper.GetBadgeSegments)
 	router.GET(baseURL+"/badge/static", wrapper.GetBadgeStatic)
+	router.GET(baseURL+"/badge/tests/:project", wrapper.GetBadgeTestsProject)
+	router.POST(baseURL+"/badge/tests/:project", wrapper.PostBadgeTestsProject)
 	router.GET(baseURL+"/openapi.yaml", wrapper.GetOpenapiYaml)
 	router.GET(baseURL+"/ping", wrapper.GetPing)
 
@@ -322,24 +1588,78 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xXTXPbNhD9K1u0tyqikvaQ4S1tOh1PW8fjuodO7MlAwJJEAgIIsFSt8ei/dxYkTcm0",
-	"bE/b9JKcROFj8R727QNwI5Rvg3foKInyRiTVYCvz54/WoKOfYvSR/2pMKppAxjtRCuRm8Ov3qAgqH0Hl",
-	"0ZDbk1iIEH3ASAbTbPKNoG1AUYpE0bha7BZ9uHt6dgsR8WNnImpRvj0IM066WoyTejQc7sy4+hxT8C7h",
-	"HPursxOQG2msXBtraAtxGArodPDG0Qx/P0KjfieJ/1c+tvwltCR8RqZFsZizSiSpy/PRdS0T8B/28E4D",
-	"NxjT/Vuzm7HjJuMqP+d16p+dMg1mspa6RqjRYZTkIySMG6NwCScapc0Z8+5ZiNiahAkan8i4+qtL3ldr",
-	"FA4b52TLa/92csEwyZDlvzk4RxR70MXz5Wq54mE+oJPBiFJ8l5sWIkhq8j4UeWqht062RnFLjTRn8nOP",
-	"G0HCMHQgtJYJNXgH1CCkLgRrUEOQUbZIGNMSXt2ZYRKsO2MJquhb0JIkVEiqQZ1bLp0EkrFGguS7qBCk",
-	"0xC8q/0L6LNMqCGi08gZgT4xaZl3iiUiGfOJzqjpB17z9cCOeY/ARPn2Lsk/zn8FX/VMMG4wAvke2y2F",
-	"Hm707VJw0kUpPnYYt2IxJqaHLvarhGKHi6GO7xXUXRxn+2QHfgxFmxSs3GatVD7j7LfUyjXaI4DGvk+5",
-	"fospyRqPIJh6/wMMCS0bnBxWVt76COvtkaVz94MLXy3EaDa5Hl6sVvyjvCN0uRBMK2ss0qb+9rq1onSd",
-	"tbvFHbTnSF10adoSrrrvZ6Ekl4fK+izep95eJmjfRKxEKb4upgOgGNy/2Lf+bDiH6/fdMPYvBMmaFS4G",
-	"v0Fxxa1DsYeIGivjWJxH6v31kGomZE0irotpWs8x5cqkBk2cCh724nBNnk2TptoDGREwJXRkpLXbQ4dI",
-	"8JehBipzjRoI22AljYvl6kpLuGhMYifpElZdds9LZ5yync6WgCoiJdaLVApTGt32QZeYoIrPWhTFzfT9",
-	"jgtpVzzlXOCz9cnHwpPSMH2dynyeP+jeFw3OJAoMn2UQcy5AQjYI1sutgfNRODnGHer/0shnFdPKLfRN",
-	"oLpEvt2vCvLAuojejrI3rmaceB2s1zgiuM/pcph0YHVSa8NApD3buzr1IWYXtETbfJNgwxWfpSv2+v0/",
-	"lf57v+Ijuv5yG/hyG/g0uh+eBcutZCpHdD84J/GBO0yAFFCZasB+n7zf9AP/5MCPbinhNRUbp29xHM/P",
-	"jOqbXw4INmjDQC7wjGOkhuvA4aNTNag+7L84Z7T4Ffs4n3+e14NX8uNsh9fsVR7Zv1h6A+miFaVoiEJZ",
-	"FNYrafk5Wb5cvVwVMphi81zsrnZ/BwAA//9E954wahAAAA==",
+	"H4sIAAAAAAAC/+x8e3PbRpL4V+kffpu6pBai5MS7lVVV6iqRvTnnYkdnObt3FaqWQ6BJjA3MIDMDSTwv",
+	"v/tV9wxeBEDRtiI7W/rHFoF59GP6PY23UaKLUitUzkanb6NSGFGgQ8O/nhqjzXciXSP9StEmRpZOahWd",
+	"Ri9RpWhgJWReGbQgLAgFSDNgSVNAKutQpKBXIOCHi59e+LczOMsl7QfXmUwyEEmCpQNRlrlMBK1+/Npq",
+	"BSK/Fhs7VwYTlFfYWcHO5iqKI0lg/Fqh2URxpESB0WmELcRxZJMMC0Ggu01Jb5da5yhUtN3G0V+1KYQb",
+	"4uWfE9AuQ4/JDM5ffO//tJAIAs1qWCIY/LVC6zCF5QZEmkq1BgGLWanWC7DVaiVvwGleqBQuA23gWroM",
+	"hJqrxbeM9ynIQqzxmKdkKFI0M1gQARZg0FVGWZ6fiyXmMRRorVgjCJVConNtbA9SkIp/2ExintqZ1HOF",
+	"Ki21VA48OWI/dxSLABw0sO0ypQFxkgErT9Yu8VFVRXT6S2Sv1lEclYr+pcWiy7hmjHVGqjXz5Ue91kOu",
+	"0FOwmb5WsMSVNtgSZQZPpcvQ8BOCgg+cAiyWmKaYgky0ItIL5f+saYTmCo1/lEqDidNmE4M2cyWU5wqk",
+	"wgn4+eUz+Nxerf94U+QxlGodw+sS17TkWq6+mKZFTqiMHMMdbM+Ij0OU+THhcvG374GWsjN4gitR5c7W",
+	"p8rhjYOkHtgeA+s2Oc72gOX3vB22v8vUZUPY+HG9Ja1HRC3lDeZ2365+te6uhbiRBR2PRycncVRIFX41",
+	"J0Mqh2s0DNBFIvIRTcSPYSWIgQRTK6wx4Gw9gy9hpQ38h3xy/gxSactcbCbBtLxHF8TU05yBCqf7NEp1",
+	"teRxDQJfd8A/mf2pQUBVxbKGn7gyhP+7DsvgVXsyw8b+DUgLlaXTvAKlHVh0Mzg3mOJKKlJAXjtVliVD",
+	"Gki0Wsl1ZTD1C8xVpXK0pE2kpdVohcmjy1NGpXiVs3jTf0f210oYGlbmwjqZREyhI5fh0bLWwTqRIh8T",
+	"9W29OpsabxPY4Awp5K2KXr7GxDEvEx4drAHtb3SJxkm0g8mDkx1HKToh8+HQ6KlIMiiNXuZYwEpXKqhE",
+	"lzVaMiYGkLpBEIZYdYVG5ERFh4Ud3S48EMaIDf3GGsmhyNEm0mBKhO6CVk9qyeiJQcudaQJhjS/R8jEd",
+	"6hH/HsjSV6TmV0YXIBRUZa4FacikHmKw1MbNBhRdGqGSbBQ7novpcN8XfPBJIMMQyKVCFrxGjKRyf34c",
+	"DYWdeJQ7MYJMJpQ3cyWaBJUjqBkfNrIGr6SubMCs1pIeeuacUJtZNCbHO9IaRwzsPqx4QPBhyJwusUb0",
+	"QBQDBsM9zlvUmn3Glp4GvjSaj8eth6weGNcsrhFvGdsCOnb6ngbvYsJLfFUbqb7DIry+iof+Cuy4K2A1",
+	"SFcTmDWg8B6RE2aN7KiRO9VM8x7bXA3OcCKSDC8w0Sod4Wt40bGhhdgwT2laSlpnlInJhPG+uOjb5eC4",
+	"zeB5lTt5ZHFdoHI7ars/ZSWNdRBGzqJ4KHvSTijMC3RBTwVkvGBbELXDHsUDpziO2J8alXJ+c3Ygrn6Z",
+	"EXgDEYZLPPcvdrxubwsZfT41xRjphEF4rdkGsq6eR/BPmEej9PJH6m9obDANfSjCixqKoePMEQk8ika9",
+	"k65Y9TeqCdsSoD43PcK2/BwTtPM6MnuCNhnRGfVr6NuNXcsYfJkB6iKvsHEwOvYOUo1W/Rs7HCGQCRuN",
+	"EXjH8vZ+Rk/aXzWJ9y7mXZGBAg4e/q3TS+EcGjUWua6rXBjAm9KgZY5fEfoWiso6KIRLsrEFWwYPV2Ra",
+	"hQX2UKoja/7JQF9uyiF28LkHgtSli4E8MaAoW+v8izFAPTYTTLYh4PZBK6/VCfoP92O2Y2dUqvVLtKVW",
+	"dgS3b8+fgbgSMhdLmUu3AROGNpI2OK9+RIrpP3yo3po+4fDIyQJHxdwJV9mu06rfjHigcXTVaoKhmRxi",
+	"13jbbOzG5fAJjZD1GRdQ7rjotzmre0XmOhOuo9RJNMfwxxtRlLlfvmHnHwyuotPo/x+3CZ/j4H8f72D2",
+	"1M8f811vl8kayxF57KaWDoOrp/PGjmBX6TJsl7czrkZv6P77F1DVtuiO2cdGJWx+K4EonUQTRX7e23C/",
+	"zo3+i+K4jkR7nb7cjG/e0qgy+ZheE+nmyOkj8k9+fvlj8HYNp/5sd8kpto/J0Su0zgcrY35YVRTCbEIO",
+	"p4lQHJmi94pOikKOBkX0PBg6WhWuhYU1KjSiDpE4YFjLK1SjzgQ5Upj2tNIeX19Ye/jgSe89juwbWZYH",
+	"r+S0E/mBY6uSlOqIdft7hmqXUjVj+vHItFK+PewIFGro2qJaozEUbaIHJpWRbnNBKsMfh/PKZq/0GxwR",
+	"SH7cTY2sdJs3tKVI0DuRi6OjsrLZzNF4u6gTIWzCUZiuSc+cK302Q6rVSObyhT56QUZO2SAk9SnThhM9",
+	"MsEZPEtR5AyMVkelwUJatJBp66Ra/z8OZnKZYDCrIUfz/Nkr5rF0OQNGi9OKUcewRY9mJ7MTGqZLVKKU",
+	"0Wn0FT9iByljgh3z1OM6C3D8NrBnSy/XOCI+3wdBAdHmDjxyTSxOGaHdxEIrzzXdPfu9q+8XkNYHE6y1",
+	"5spHRX6VNnVNmWCen/h8wFQGwC/cQCFt7YDk2KZQizIXjisYc7Uwi7jN+aw4KoVFvcACPueE4uIvX82+",
+	"/GzxRQyLEBsvYlhw4LyIw3BM/Z+kZxYM+IJzGou5Cov88dHsT58tYsCidJs2rSQtKL2LSMgyk9bjbPyz",
+	"lLngw+46w3PeSFXXlPwyCBW0WmvOiRbCgZdQokZIjba8YX6EHHs389zSYzLZG+KdPenlD4OqCad7cL19",
+	"C2bWcHu7nYSvjcPuAEKLOSauzmmEUHi56cMmwvMajRrKKQiT2xP07whfE5m3EO7j3tm7A3CxI5VStaI3",
+	"maMTFgRYzr50cx1jcLH83FLXG3coW3k49kn4AwZyIerAcZ5YBw72VZADBocS5SFIcdXigIGdiu72Mo7q",
+	"CIzNwJcnJ/RfopULacnd8h89a4m/z3PvpwXZOtZlzuhUVXlePwh1Nf9w4M2+7FRAvYe5jaPHJ4/vDM5u",
+	"2WG7Hez/QrcnOBMWlohqyogxlk6sSeVGtScZXW5v08ZeafeUAiwx12pNimMGYYAFm4lQ9yTfBLxvUscV",
+	"Ow4MPbKi8M8beSKL34pT64S1rpkzFb6TzH/HuE9Dv1ux3KltBfs/IfCNczgN0GUcldqOuCg/B/Uy8EF2",
+	"XY/vA4+hNHolc7Qx5Im+AmdEguHBmV6icZUR8N/Pf2Rj/oM402eafs6VX9inIuvkivdVWOdK1aFNvwjT",
+	"8arJN+FydSBUJi0VpJuw2kM74gWca/tebkD/okOIreAJOkxG4WvrjhPc8gP/On0BQFROR3HERXEiMadB",
+	"A2GjOHotEp3o0VsB7xe+vcHS1RWGcXJOGl9a/rZzFzKk3+l0s0cf6cShO7LOoCj6eqmJm5ZSCd5+gHdv",
+	"JVaTb/cJZ0SlluMyF1LtH7ndlfntb2gLdgqUI2q256QTJ4lXmHplf3Jfyt6/hvCetn50X1u/6il10gWF",
+	"tJb8N21AqkQbE5Inj0++uleggnXKhI9KWhjbIJrBenSvYLU602kNuTBr7GUDWN118gC/XG4vW+NMWESX",
+	"ND5EvulGiUImh4S7YWjw9ZeCsms6FDErQhnTTvZtBt/uzJAWlpXMndevfL9ohc6XGI0u5krUhU2rK5P4",
+	"m15l17Mn/eYzcHQ+vEhbH+immGg2IX6FJr8+EvfOVTfwhYVZ7IswnwQC3WJSKEWoV10777RHr6GCx9jo",
+	"Ykr3etg/zCk5JJZc6d0g935j2d7+bTz6saLVjxiG3nEU+oRPWM+52ZGHGSzIFVkEkHx4EWZ03B4/GM68",
+	"Tjui0lwMzqCgpBxdpzKY6LWS/8s3aHgQEHQsUHRbdfp6lQdoxFVqCrS1s7TjO7EmjaONKLjArvm/G/8D",
+	"b0IBrUCXYWUP8qR8vaBTCkWqHrITJdZCKus5NqFZvNoxbNj3Z9fmirTMr4sZPG32IjsvDFEzZF3/sGAf",
+	"mmh3LlwWg3YZmmvpb0ds/MvnTy/o5TRx6597z8xDluBfPkvwkRzH8SxA62y0JcVJf+NJsBM+fLSsx8rB",
+	"Xc8QYErTuSnQWYduyXcuiLbsZTFCa1E5KfJ80/dQrJfFlbzBtBVfvxkLP/sa/gppZXFVcd1irqRK8opv",
+	"wFtMDIasZ5KgtXWdw+5zMVpQow88ce9T8p4oME8ctf2M+Z2cvuO37d//INW5PT7EAbZOuIP934P43f5F",
+	"9whu8zF9VNInub/273TomAABbAPoYE7mvnqof6C7ORBNujvoH0FSWae7d2wITjoXRue1fEm1nsF5X0CT",
+	"DJM3fSsszVylzf0S61NMdAF50w8Xr0QuO1wgSaXDimmdDAkXoJs7CdyWw7zCmzLXKdZEGDOvvK7t2dfx",
+	"uwp+iUH5318t950i0YMpfjDFH1EZVjYjRdgkz7f+74MK3zv1br5056veftnaBeYMPNQZHkx9I9ttpegQ",
+	"kX+3aVLlne6eTF97Ei84SKJCc4gRF1xzzrUJJec2iFo01wo9FAzvXoPM417UpDlEN394ZZkruPxiX/n2",
+	"Y5aXw6tPq7pcV77p3R7A7qWoPMXQs9ugOzzgf7AYDyXeuyvxuozOdFPeHWrv96zsNqpzp62Bu8zaei1t",
+	"LJ0Ffe339vn1Cb+1mfZhHuvgwi4HfcE7bPbYA8M7bU812mqsz4mwFcFy9hRwDAbLXCSsbLqXRrzVYmvq",
+	"p5EdVRtvU/lJDJWtOKwVKrQMzlVTE38fgxkg8pe23s5r9T6PTmEe/eWrz+ZRDHOvW/2ztUFU82hLBjzc",
+	"uQ+t7gaplcJDZWOwVZKFCzBesWoDvokr9t2F3PAc+p3CtrBsXILRanC1z3ofVrIcyNdBBcPH491fnk0P",
+	"Rb1PqKjX1z2fUFmvEek7qerVrWPvnNUIn0HYBFkkPVCvBSud5/q61kvhqwykGuZq8e/88xuq76Xz6uTk",
+	"yz+Had/kUlU3/hHriW+8iuiNuZYq1de2O4oukQZ7USspaaEUJIbNPZ+5WoQlFk07Jan3UlsOyvf5+Bc1",
+	"ie7esf/tXff7KiiN7W+72EsFSDwKbDgsjdIOnjakh/eUD5IqB6LQoWHTwtnFxVPzAHSGocUHAP/g3z9k",
+	"hD5ORsjbgfvMg1/4He9AAT/caPiXudHwoAAfFODHUYAOrbPv1gE21vjV6decui9fR9S8I/wTHj/6EnwT",
+	"YAxfge8CpAi61xpm/AcKyDnnLfyw7jWUcA37ttz6oMuL3Gjfe0opAQ8I/RUAiWER+hHpT25IDBmD0OK1",
+	"z82mjld7b91ZTNBPMnceCPxpd2aFY/T7bsx6MCAPGfI7bILqqvN76IPyJsG3Ef2+eqAGkH/8/qcu73Yt",
+	"8ESWvVaB8MPPSjrueWp6nEJXk65cWTnQq7larMMBOfKfCO22Qe1tWHoXs/jRupVeV4pbgdZ315n00HZ0",
+	"H21H3c+MTLaR8Gu4RoMP5YmHnqP76TkKX+KY8SX+qfjK1BdNpYUwAWyJiVwFBMeCjZ/8wP/x3QG3SBYL",
+	"7ZVKGzj2ym0fup/+s2flM8zLgFxJM6aQCveA+18B44uO3U+ADdCiz4pFv6Gm6H227HZsw+fFLree+2TP",
+	"vcXibyjxB2FOj49znYg809adfn3y9cmxKOXx1aNoe7n9vwEANOHQtnNdAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
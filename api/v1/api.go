@@ -2,9 +2,12 @@ package api
 
 import (
 	"crypto/sha256"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -17,15 +20,23 @@ import (
 	"github.com/tdewolff/minify"
 	"github.com/tdewolff/minify/svg"
 	"github.com/wrouesnel/badgeserv/pkg/badges"
+	"github.com/wrouesnel/badgeserv/pkg/cache"
+	"github.com/wrouesnel/badgeserv/pkg/dataformat"
+	"github.com/wrouesnel/badgeserv/pkg/pongo2utils"
+	"github.com/wrouesnel/badgeserv/pkg/query"
 	"github.com/wrouesnel/badgeserv/pkg/server/badgeconfig"
+	"github.com/wrouesnel/badgeserv/pkg/store"
 	"github.com/wrouesnel/badgeserv/version"
 	"go.withmatt.com/httpheaders"
+	"golang.org/x/sync/singleflight"
 )
 
 //go:generate bash -c "oapi-codegen -package api openapi.yaml > api.gen.go"
 
 var (
-	ErrPredefinedBadgeNotFound = errors.New("Predefined badge name not found")
+	ErrPredefinedBadgeNotFound    = errors.New("Predefined badge name not found")
+	ErrPredefinedBadgeCredentials = errors.New("Predefined badge credentials could not be resolved")
+	ErrPredefinedBadgeParameters  = errors.New("Predefined badge parameters are invalid")
 )
 
 // ApiImpl implements the actual nmap-api.
@@ -34,176 +45,459 @@ type apiImpl struct {
 	badgeService     badges.BadgeService
 	minify           *minify.M
 	httpClient       *resty.Client
-	predefinedBadges *badgeconfig.Config
+	trustedClient    *resty.Client
+	responseCache    *cache.Cache[interface{}]
+	inflight         *singleflight.Group
+	staleConfig      StaleConfig
+	errorBadges      bool
+	dynamicDisabled  bool
+	predefinedBadges func() *badgeconfig.Config
+	pushConfig       PushConfig
+	pushStore        store.Store
+	coverageConfig   CoverageConfig
+	coverageStore    store.Store
+	coverageMtx      *sync.Mutex
+	testsConfig      TestsConfig
+	testsStore       store.Store
 	logger           *zap.Logger
 }
 
 const DynamicBadgeResponseName = "r"
 
+// DynamicBadgeQueryName is the template variable holding the result of the badge query.
+const DynamicBadgeQueryName = "q"
+
+// StaleConfig configures how badges rendered from stale target responses are marked.
+type StaleConfig struct {
+	Color  string `help:"Color for badges rendered from stale target responses (empty keeps the badge color)" default:""`
+	Suffix string `help:"Suffix appended to the message of badges rendered from stale target responses" default:""`
+}
+
+// renderOptions carries badge adjustments which are not controlled by the badge templates.
+type renderOptions struct {
+	// Stale is set when the badge was rendered from an expired target response.
+	Stale bool
+	// ErrorBadge renders failures as error badges for clients which do not accept JSON.
+	ErrorBadge bool
+	// ErrorLabel is the label used on error badges.
+	ErrorLabel string
+	// Style is the badge style, also used for error badges. Empty uses the server default.
+	Style string
+	// Logo, LogoColor and LogoWidth configure the badge logo. No logo is shown if Logo is empty.
+	Logo      string
+	LogoColor string
+	LogoWidth int
+	// Font is the path of the font file for the badge text. Empty uses the server font.
+	Font string
+	// ColorRules choose the badge color from the badge value if set.
+	ColorRules *badgeconfig.ColorRules
+	// Format is the image format of the badge and Scale the size multiplier of PNG badges.
+	Format string
+	Scale  float64
+	// MaxAge is how long clients may cache the badge for. Zero requires clients to revalidate.
+	MaxAge time.Duration
+	// CacheTTL is how long the target response of the badge is cached by the server. It is reported as the
+	// cacheSeconds of JSON responses if MaxAge isn't set.
+	CacheTTL time.Duration
+	// IsError marks error badges in JSON responses.
+	IsError bool
+}
+
+// renderOptionsFrom returns the render options set by the query parameters shared by the badge endpoints.
+func (a *apiImpl) renderOptionsFrom(errorBadge *bool, errorLabel string, style string, logo *string, logoColor *string,
+	logoWidth *int, scale *float64, format string,
+) renderOptions {
+	return renderOptions{
+		ErrorBadge: lo.FromPtrOr(errorBadge, a.errorBadges),
+		ErrorLabel: errorLabel,
+		Style:      style,
+		Logo:       lo.FromPtr(logo),
+		LogoColor:  lo.FromPtr(logoColor),
+		LogoWidth:  lo.FromPtr(logoWidth),
+		Format:     format,
+		Scale:      lo.FromPtr(scale),
+	}
+}
+
 func (a *apiImpl) generateETag(in []byte) string {
 	return fmt.Sprintf("sha256:%x", sha256.Sum256(in))
 }
 
 func (a *apiImpl) GetBadgeDynamic(ctx echo.Context, params GetBadgeDynamicParams) error {
-	target := params.Target
-
-	a.logger.Debug("Making outbound request", zap.String("target", target))
-	resp, err := a.httpClient.NewRequest().Get(target)
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, errorLabel(params.Label, errorBadgeLabel),
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	if a.dynamicDisabled {
+		return a.errorResponse(ctx, opts, newRequestError(http.StatusForbidden, "Dynamic badge targets are disabled",
+			"dynamic badges disabled", ErrDynamicTargetsDisabled))
+	}
+	targetFormat, err := dataformat.Parse(string(lo.FromPtr(params.TargetFormat)))
 	if err != nil {
-		a.logger.Debug("Outbound HTTP request failed", zap.Error(err))
-		return ctx.JSON(http.StatusBadGateway, &ClientError{
-			Description: "Target HTTP request failed",
-			Error:       err.Error(),
-		})
+		return a.errorResponse(ctx, opts, newRequestError(http.StatusBadRequest, "Target format is invalid",
+			"invalid target format", err))
 	}
+	return a.errorResponse(ctx, opts, a.getDynamicBadge(ctx, params, nil, targetOptions{Format: targetFormat}, opts))
+}
 
-	var responseData interface{}
-	if err := json.Unmarshal(resp.Body(), &responseData); err != nil {
-		return ctx.JSON(http.StatusBadGateway, &ClientError{
-			Description: "Response could not be unmarshalled to JSON",
-			Error:       err.Error(),
-		})
+// getDynamicBadge renders a dynamic badge. segments replace the message and color if not empty, and targetOpts
+// configures the request for the badge target.
+func (a *apiImpl) getDynamicBadge(ctx echo.Context, params GetBadgeDynamicParams, segments []segmentTemplate, targetOpts targetOptions, opts renderOptions) error {
+	responseData, stale, err := a.fetchTarget(params.Target, targetOpts)
+	if err != nil {
+		return err
 	}
 
 	templateCtx := map[string]interface{}{}
 	templateCtx[DynamicBadgeResponseName] = responseData
 
+	if queryExpr := lo.FromPtr(params.Query); queryExpr != "" {
+		compiled, err := query.Compile(queryExpr)
+		if err != nil {
+			return newRequestError(http.StatusBadRequest, "Query is invalid", "invalid query", err)
+		}
+		result, err := compiled.Evaluate(responseData)
+		if err != nil {
+			return newRequestError(http.StatusBadRequest, "Query could not be evaluated against the target response",
+				"query failed", err)
+		}
+		templateCtx[DynamicBadgeQueryName] = result
+	}
+
+	opts.Stale = stale
+	if !stale {
+		opts.CacheTTL = a.cacheTTL(targetOpts)
+	}
 	return a.getBadge(ctx, GetBadgeStaticParams{
-		Label:   params.Label,
-		Message: params.Message,
-		Color:   params.Color,
-	}, templateCtx)
+		Label:      params.Label,
+		Message:    params.Message,
+		Color:      params.Color,
+		LabelColor: params.LabelColor,
+	}, segments, templateCtx, opts)
 }
 
 func (a *apiImpl) GetBadgePredefined(ctx echo.Context) error {
-	//TODO implement me
-	panic("implement me")
+	// Example URLs are made absolute relative to the listing endpoint so clients can use them directly.
+	listingURL := url.URL{
+		Scheme: ctx.Scheme(),
+		Host:   ctx.Request().Host,
+		Path:   strings.TrimRight(ctx.Request().URL.Path, "/"),
+	}
+
+	predefinedConfig := a.predefinedBadges()
+	predefinedBadges := make([]PredefinedBadgeDesc, 0, len(predefinedConfig.PredefinedBadges))
+	for predefinedName, badgeDef := range predefinedConfig.PredefinedBadges {
+		parameters := lo.MapToSlice(badgeDef.Parameters, func(name string, parameter badgeconfig.Parameter) ParameterDesc {
+			desc := ParameterDesc{
+				Name:        lo.ToPtr(name),
+				Description: lo.ToPtr(parameter.Description),
+				Type:        lo.ToPtr(string(lo.Ternary(parameter.Type != "", parameter.Type, badgeconfig.ParameterString))),
+				Required:    lo.ToPtr(parameter.Required),
+			}
+			if defaultValue, ok := parameter.DefaultValue(); ok {
+				desc.Default = lo.ToPtr(defaultValue)
+			}
+			if len(parameter.Values) > 0 {
+				desc.Values = lo.ToPtr(parameter.Values)
+			}
+			if parameter.Pattern.Regexp != nil {
+				desc.Pattern = lo.ToPtr(parameter.Pattern.String())
+			}
+			return desc
+		})
+		sort.Slice(parameters, func(i, j int) bool {
+			return strings.Compare(*parameters[i].Name, *parameters[j].Name) < 0
+		})
+
+		examples := lo.Map(badgeDef.Examples, func(example badgeconfig.BadgeExample, _ int) PredefinedBadgeExample {
+			exampleURL := listingURL
+			exampleURL.Path = fmt.Sprintf("%s/%s/", listingURL.Path, predefinedName)
+			exampleURL.RawQuery = example.Query().Encode()
+
+			return PredefinedBadgeExample{
+				Description: lo.ToPtr(example.Description),
+				Parameters:  &PredefinedBadgeExample_Parameters{AdditionalProperties: example.Parameters},
+				Url:         lo.ToPtr(exampleURL.String()),
+			}
+		})
+
+		predefinedBadges = append(predefinedBadges, PredefinedBadgeDesc{
+			Name:        predefinedName,
+			Description: lo.ToPtr(badgeDef.Description),
+			Parameters:  &parameters,
+			Examples:    &examples,
+		})
+	}
+
+	sort.Slice(predefinedBadges, func(i, j int) bool {
+		return strings.Compare(predefinedBadges[i].Name, predefinedBadges[j].Name) < 0
+	})
+
+	return ctx.JSON(http.StatusOK, predefinedBadges)
 }
 
 func (a *apiImpl) GetBadgePredefinedPredefinedName(ctx echo.Context, predefinedName string, params GetBadgePredefinedPredefinedNameParams) error {
-	badgeDef, ok := a.predefinedBadges.PredefinedBadges[predefinedName]
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, predefinedName,
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	// The configuration is read once so a reload can't change it during the request.
+	predefinedConfig := a.predefinedBadges()
+	if badgeDef, ok := predefinedConfig.PredefinedBadges[predefinedName]; ok {
+		opts.ErrorLabel = errorLabel(&badgeDef.Label, predefinedName)
+		opts.Style = lo.Ternary(opts.Style != "", opts.Style, badgeDef.Style)
+		opts.Logo = lo.Ternary(opts.Logo != "", opts.Logo, badgeDef.Logo)
+		opts.LogoColor = lo.Ternary(opts.LogoColor != "", opts.LogoColor, badgeDef.LogoColor)
+		opts.LogoWidth = lo.Ternary(opts.LogoWidth != 0, opts.LogoWidth, badgeDef.LogoWidth)
+		opts.Font = badgeDef.Font
+		opts.ColorRules = badgeDef.ColorRules
+	}
+	return a.errorResponse(ctx, opts, a.getPredefinedBadge(ctx, predefinedConfig, predefinedName, opts))
+}
+
+func (a *apiImpl) getPredefinedBadge(ctx echo.Context, predefinedConfig *badgeconfig.Config, predefinedName string, opts renderOptions) error {
+	badgeDef, ok := predefinedConfig.PredefinedBadges[predefinedName]
 	if !ok {
-		return ctx.JSON(http.StatusNotFound, &ClientError{
-			Description: "Predefined badge with given name does not exist",
-			Error:       ErrPredefinedBadgeNotFound.Error(),
-		})
+		return newRequestError(http.StatusNotFound, "Predefined badge with given name does not exist",
+			"badge not found", ErrPredefinedBadgeNotFound)
 	}
 
 	targetTemplate, err := pongo2.FromBytes([]byte(badgeDef.Target))
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, &ClientError{
-			Description: "Predefined badge target template failed to parse",
-			Error:       err.Error(),
-		})
+		return newRequestError(http.StatusInternalServerError, "Predefined badge target template failed to parse",
+			"invalid badge definition", err)
 	}
 
-	// This code should handle things for us .. but it doesn't work with the current generator. So instead parse
-	// from the URL directly.
-	//queryParams := map[string]interface{}{}
-	//if params.Params != nil {
-	//	if params.Params.AdditionalProperties != nil {
-	//		queryParams = params.Params.AdditionalProperties
-	//	}
-	//}
-	queryParams := lo.MapEntries(ctx.Request().URL.Query(), func(queryParamName string, v []string) (string, interface{}) {
-		value := ""
-		if len(v) > 0 {
-			value = v[0]
-		}
+	// The parameters are defined by the badge rather than the API, so are read from the URL directly.
+	queryParams, paramErrs := badgeDef.TargetParameters(ctx.Request().URL.Query())
+	if len(paramErrs) > 0 {
+		details := lo.Map(paramErrs, func(err error, _ int) string {
+			return err.Error()
+		})
+		reqErr := newRequestError(http.StatusBadRequest, "Predefined badge parameters are invalid", "invalid parameters",
+			errors.Wrap(ErrPredefinedBadgeParameters, strings.Join(details, "; ")))
+		reqErr.Details = details
+		return reqErr
+	}
 
-		return queryParamName, value
-	})
+	target, err := targetTemplate.Execute(queryParams)
+	if err != nil {
+		return newRequestError(http.StatusInternalServerError, "Predefined badge target template failed to execute",
+			"invalid badge definition", err)
+	}
 
-	target, err := targetTemplate.Execute(lo.PickByKeys(queryParams, lo.Keys(badgeDef.Parameters)))
+	headers, err := badgeDef.RequestHeaders()
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, &ClientError{
-			Description: "Predefined badge target template failed to execute",
-			Error:       err.Error(),
-		})
+		// The error may describe where secrets are kept, so it is only logged.
+		a.logger.Error("Predefined badge request headers could not be resolved",
+			zap.String("predefined_name", predefinedName), zap.Error(err))
+		return newRequestError(http.StatusInternalServerError, "Predefined badge credentials could not be resolved",
+			"invalid badge definition", ErrPredefinedBadgeCredentials)
 	}
 
-	return a.GetBadgeDynamic(ctx, GetBadgeDynamicParams{
-		Target:  target,
-		Label:   &badgeDef.Label,
-		Message: &badgeDef.Message,
-		Color:   &badgeDef.Color,
+	segments := lo.Map(badgeDef.Segments, func(segment badgeconfig.Segment, _ int) segmentTemplate {
+		return segmentTemplate{Message: segment.Message, Color: segment.Color}
 	})
+
+	return a.getDynamicBadge(ctx, GetBadgeDynamicParams{
+		Target:     target,
+		Query:      &badgeDef.Query,
+		Label:      &badgeDef.Label,
+		Message:    &badgeDef.Message,
+		Color:      &badgeDef.Color,
+		LabelColor: &badgeDef.LabelColor,
+	}, segments, targetOptions{
+		Headers:  headers,
+		Format:   badgeDef.TargetFormat,
+		CacheTTL: badgeDef.CacheTTL,
+		Trusted:  true,
+	}, opts)
 }
 
 func (a *apiImpl) GetBadgeStatic(ctx echo.Context, params GetBadgeStaticParams) error {
-	return a.getBadge(ctx, params, nil)
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, errorLabel(params.Label, errorBadgeLabel),
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	return a.errorResponse(ctx, opts, a.getBadge(ctx, params, nil, nil, opts))
 }
 
-func (a *apiImpl) parseTemplate(ctx echo.Context, paramName string, templateString string) (*pongo2.Template, error) {
-	tmpl, err := pongo2.FromBytes([]byte(templateString))
+func (a *apiImpl) GetBadgeSegments(ctx echo.Context, params GetBadgeSegmentsParams) error {
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, errorLabel(params.Label, errorBadgeLabel),
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+
+	// Colors are paired with segments by position. Segments without a color use the default color.
+	colors := lo.FromPtr(params.Color)
+	segments := lo.Map(params.Segment, func(message string, idx int) segmentTemplate {
+		color := ""
+		if idx < len(colors) {
+			color = colors[idx]
+		}
+		return segmentTemplate{Message: message, Color: color}
+	})
+
+	return a.errorResponse(ctx, opts, a.getBadge(ctx, GetBadgeStaticParams{
+		Label:      params.Label,
+		LabelColor: params.LabelColor,
+	}, segments, nil, opts))
+}
+
+// parseTemplate parses a badge text template. Badge text is escaped for the output format when the badge
+// is written, so templates don't HTML escape it.
+func (a *apiImpl) parseTemplate(paramName string, templateString string) (*pongo2.Template, error) {
+	tmpl, err := pongo2utils.FromStringUnescaped(templateString)
 	if err != nil {
-		return nil, ctx.JSON(http.StatusBadRequest, &ClientError{
-			Description: fmt.Sprintf("%s template is invalid", paramName),
-			Error:       err.Error(),
-		})
+		return nil, newRequestError(http.StatusBadRequest, fmt.Sprintf("%s template is invalid", paramName),
+			fmt.Sprintf("invalid %s template", strings.ToLower(paramName)), err)
 	}
 	return tmpl, nil
 }
 
-func (a *apiImpl) executeTemplate(ctx echo.Context, paramName string, template *pongo2.Template, templateCtx pongo2.Context) (string, error) {
+func (a *apiImpl) executeTemplate(paramName string, template *pongo2.Template, templateCtx pongo2.Context) (string, error) {
 	// Execute the templates
 	result, err := template.Execute(templateCtx)
 	if err != nil {
-		return "", ctx.JSON(http.StatusBadRequest, &ClientError{
-			Description: fmt.Sprintf("%s template execution failed", paramName),
-			Error:       err.Error(),
-		})
+		return "", newRequestError(http.StatusBadRequest, fmt.Sprintf("%s template execution failed", paramName),
+			fmt.Sprintf("%s template failed", strings.ToLower(paramName)), err)
 	}
 	return result, nil
 }
 
-func (a *apiImpl) getBadge(ctx echo.Context, params GetBadgeStaticParams, templateCtx pongo2.Context) error {
+// segmentTemplate holds the templates of a badge segment following the label.
+type segmentTemplate struct {
+	Message string
+	Color   string
+}
+
+// renderTemplate parses and executes a template.
+func (a *apiImpl) renderTemplate(paramName string, templateString string, templateCtx pongo2.Context) (string, error) {
+	tmpl, err := a.parseTemplate(paramName, templateString)
+	if err != nil {
+		return "", err
+	}
+	return a.executeTemplate(paramName, tmpl, templateCtx)
+}
+
+// getBadge renders the badge templates and writes the badge response. If segments is empty the badge has a
+// single segment from the message and color parameters. Failures are returned as a requestError for
+// errorResponse to report.
+func (a *apiImpl) getBadge(ctx echo.Context, params GetBadgeStaticParams, segments []segmentTemplate, templateCtx pongo2.Context, opts renderOptions) error {
 	if templateCtx == nil {
 		templateCtx = map[string]interface{}{}
 	}
+	if len(segments) == 0 {
+		segments = []segmentTemplate{{Message: lo.FromPtr(params.Message), Color: lo.FromPtr(params.Color)}}
+	}
 
-	// Parse the incoming templates
-	labelTmpl, err := a.parseTemplate(ctx, "Label", lo.FromPtr(params.Label))
+	label, err := a.renderTemplate("Label", lo.FromPtr(params.Label), templateCtx)
 	if err != nil {
 		return err
 	}
-	messageTmpl, err := a.parseTemplate(ctx, "Message", lo.FromPtr(params.Message))
+	labelColor, err := a.renderTemplate("Label color", lo.FromPtr(params.LabelColor), templateCtx)
 	if err != nil {
 		return err
 	}
-	colorTmpl, err := a.parseTemplate(ctx, "Color", lo.FromPtr(params.Color))
-	if err != nil {
-		return err
+
+	badgeSegments := make([]badges.Segment, 0, len(segments))
+	for idx, segment := range segments {
+		messageName, colorName := "Message", "Color"
+		if len(segments) > 1 {
+			messageName, colorName = fmt.Sprintf("Segment %d", idx+1), fmt.Sprintf("Segment %d color", idx+1)
+		}
+
+		message, err := a.renderTemplate(messageName, segment.Message, templateCtx)
+		if err != nil {
+			return err
+		}
+		color, err := a.renderTemplate(colorName, segment.Color, templateCtx)
+		if err != nil {
+			return err
+		}
+
+		if opts.ColorRules != nil {
+			color, err = a.applyColorRules(opts.ColorRules, message, color, templateCtx)
+			if err != nil {
+				return err
+			}
+		}
+		if opts.Stale && a.staleConfig.Color != "" {
+			color = a.staleConfig.Color
+		}
+
+		badgeSegments = append(badgeSegments, badges.Segment{Text: message, Color: color})
 	}
 
-	// Execute the templates
-	label, err := a.executeTemplate(ctx, "Label", labelTmpl, templateCtx)
-	if err != nil {
-		return err
+	if opts.Stale {
+		badgeSegments[len(badgeSegments)-1].Text += a.staleConfig.Suffix
 	}
-	message, err := a.executeTemplate(ctx, "Message", messageTmpl, templateCtx)
-	if err != nil {
-		return err
+
+	return a.renderBadge(ctx, badges.BadgeDesc{
+		Title:      label,
+		Segments:   badgeSegments,
+		LabelColor: labelColor,
+		Style:      opts.Style,
+		Logo:       opts.Logo,
+		LogoColor:  opts.LogoColor,
+		LogoWidth:  opts.LogoWidth,
+		Font:       opts.Font,
+	}, opts)
+}
+
+// renderBadge writes the badge response, returning failures as a requestError.
+func (a *apiImpl) renderBadge(ctx echo.Context, desc badges.BadgeDesc, opts renderOptions) error {
+	err := a.writeBadge(ctx, desc, opts)
+	if errors.Is(err, badges.ErrUnknownStyle) {
+		return newRequestError(http.StatusBadRequest, "Badge style is invalid", "invalid style", err)
+	}
+	if errors.Is(err, badges.ErrUnknownLogo) || errors.Is(err, badges.ErrInvalidLogo) {
+		return newRequestError(http.StatusBadRequest, "Badge logo is invalid", "invalid logo", err)
+	}
+	if errors.Is(err, badges.ErrInvalidFont) {
+		return newRequestError(http.StatusInternalServerError, "Badge font could not be loaded", "invalid font", err)
+	}
+	if errors.Is(err, badges.ErrInvalidScale) {
+		return newRequestError(http.StatusBadRequest, "Badge scale is invalid", "invalid scale", err)
 	}
-	color, err := a.executeTemplate(ctx, "Color", colorTmpl, templateCtx)
 	if err != nil {
-		return err
+		return newRequestError(http.StatusInternalServerError, "Badge generation failed", "badge generation failed", err)
 	}
+	return nil
+}
 
-	// Create the badge
-	badge, err := a.badgeService.CreateBadge(badges.BadgeDesc{Title: label, Text: message, Color: color})
-	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, &ClientError{
-			Description: "Badge generation failed",
-			Error:       err.Error(),
-		})
+// applyColorRules returns the color chosen by the color rules, or color if no rule applies. The
+// rules are checked against the message unless they template their own value.
+func (a *apiImpl) applyColorRules(rules *badgeconfig.ColorRules, message string, color string, templateCtx pongo2.Context) (string, error) {
+	value := message
+	if rules.Value != "" {
+		valueTmpl, err := pongo2utils.FromStringUnescaped(rules.Value)
+		if err != nil {
+			return "", newRequestError(http.StatusInternalServerError, "Color rules value template failed to parse",
+				"invalid badge definition", err)
+		}
+		value, err = valueTmpl.Execute(templateCtx)
+		if err != nil {
+			return "", newRequestError(http.StatusInternalServerError, "Color rules value template failed to execute",
+				"invalid badge definition", err)
+		}
 	}
 
-	// Do the SVG response
-	return a.svgResponse(ctx, badge)
+	if ruleColor, ok := rules.Color(value); ok {
+		return ruleColor, nil
+	}
+	return color, nil
 }
 
-func (a *apiImpl) svgResponse(ctx echo.Context, svgData string) error {
+func (a *apiImpl) svgResponse(ctx echo.Context, svgData string, cacheControl string) error {
 	minifiedSvg, err := a.minify.Bytes("image/svg+xml", []byte(svgData))
 	if err != nil {
 		return ctx.JSON(http.StatusInternalServerError, &ClientError{
@@ -213,20 +507,41 @@ func (a *apiImpl) svgResponse(ctx echo.Context, svgData string) error {
 	}
 
 	ctx.Response().Header().Set(httpheaders.Etag, a.generateETag([]byte(svgData)))
-	ctx.Response().Header().Set(httpheaders.CacheControl, "no-cache")
+	ctx.Response().Header().Set(httpheaders.CacheControl, cacheControl)
 	return ctx.Blob(http.StatusOK, "image/svg+xml", minifiedSvg)
 }
 
 // Config provides the up-front configuration necessary to launch an API.
 type Config struct {
-	BadgeService     badges.BadgeService
-	HTTPClient       *resty.Client
-	PredefinedBadges *badgeconfig.Config
+	BadgeService  badges.BadgeService
+	HTTPClient    *resty.Client
+	// TrustedClient requests targets configured by the operator, which bypass the target filter. It must not
+	// share connections with HTTPClient, so connections to internal services are never reused for other targets.
+	TrustedClient *resty.Client
+	ResponseCache *cache.Cache[interface{}]
+	Stale         StaleConfig
+	ErrorBadges   bool
+	// DisableDynamicTargets rejects /badge/dynamic requests. Predefined badges still fetch their targets.
+	DisableDynamicTargets bool
+	// PredefinedBadges returns the current predefined badge configuration, which may be reloaded while the
+	// server runs.
+	PredefinedBadges func() *badgeconfig.Config
+	// Push configures the push API, and PushStore keeps the pushed values.
+	Push      PushConfig
+	PushStore store.Store
+	// Coverage configures coverage report uploads, and CoverageStore keeps the coverage history of each branch.
+	Coverage      CoverageConfig
+	CoverageStore store.Store
+	// Tests configures test report uploads, and TestsStore keeps the test results of each branch.
+	Tests      TestsConfig
+	TestsStore store.Store
 }
 
 // NewAPI returns the API server instance and the version prefix.
 func NewAPI(apiConfig *Config) (ServerInterface, string) {
-	if apiConfig.BadgeService == nil {
+	if apiConfig.BadgeService == nil || apiConfig.ResponseCache == nil || apiConfig.PredefinedBadges == nil ||
+		apiConfig.PushStore == nil ||
+		apiConfig.CoverageStore == nil || apiConfig.TestsStore == nil {
 		return nil, "err"
 	}
 
@@ -240,7 +555,20 @@ func NewAPI(apiConfig *Config) (ServerInterface, string) {
 		apiConfig.BadgeService,
 		minifier,
 		apiConfig.HTTPClient,
+		apiConfig.TrustedClient,
+		apiConfig.ResponseCache,
+		new(singleflight.Group),
+		apiConfig.Stale,
+		apiConfig.ErrorBadges,
+		apiConfig.DisableDynamicTargets,
 		apiConfig.PredefinedBadges,
+		apiConfig.Push,
+		apiConfig.PushStore,
+		apiConfig.Coverage,
+		apiConfig.CoverageStore,
+		new(sync.Mutex),
+		apiConfig.Tests,
+		apiConfig.TestsStore,
 		zap.L().With(zap.String("app_version", version.Version), zap.String("api_version", apiVersion)),
 	}, apiVersion
 }
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/coverage"
+	"github.com/wrouesnel/badgeserv/pkg/server/badgeconfig"
+	"github.com/wrouesnel/badgeserv/pkg/store"
+)
+
+var (
+	ErrNoCoverage = errors.New("no coverage has been uploaded")
+)
+
+const (
+	coverageDefaultLabel   = "coverage"
+	coverageDefaultMessage = "{{ r.coverage }}"
+	coverageDeltaMessage   = "{{ r.delta }}"
+)
+
+// coverageColorRules color coverage badges by their percentage.
+//
+//nolint:gochecknoglobals,gomnd
+var coverageColorRules = badgeconfig.ColorRules{
+	Rules: []badgeconfig.ColorRule{
+		{GTE: lo.ToPtr(95.0), Color: "brightgreen"},
+		{GTE: lo.ToPtr(90.0), Color: "green"},
+		{GTE: lo.ToPtr(75.0), Color: "yellowgreen"},
+		{GTE: lo.ToPtr(60.0), Color: "yellow"},
+		{GTE: lo.ToPtr(40.0), Color: "orange"},
+	},
+	Default: "red",
+}
+
+// CoverageConfig configures coverage report uploads.
+type CoverageConfig struct {
+	History int   `help:"Number of uploads kept for each branch" default:"20"`
+	MaxSize int64 `help:"Maximum size of an uploaded coverage report in bytes" default:"33554432"`
+}
+
+// coverageEntry is the coverage of one upload.
+type coverageEntry struct {
+	Lines   int64     `json:"lines"`
+	Covered int64     `json:"covered"`
+	Commit  string    `json:"commit,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+// Percent returns the percentage of lines covered, rounded to one decimal place.
+func (e coverageEntry) Percent() float64 {
+	return math.Round(coverage.Report{Lines: e.Lines, Covered: e.Covered}.Percent()*10) / 10 //nolint:gomnd
+}
+
+// coverageHistory is the stored value of a branch. The latest upload is last.
+type coverageHistory struct {
+	History []coverageEntry `json:"history"`
+}
+
+// latest returns the last upload and the one before it, if any.
+func (h coverageHistory) latest() (coverageEntry, *coverageEntry) {
+	current := h.History[len(h.History)-1]
+	if len(h.History) < 2 { //nolint:gomnd
+		return current, nil
+	}
+	return current, &h.History[len(h.History)-2]
+}
+
+// coverageDelta returns the change in percentage from previous to current.
+func coverageDelta(current coverageEntry, previous coverageEntry) float64 {
+	return math.Round((current.Percent()-previous.Percent())*10) / 10 //nolint:gomnd
+}
+
+// formatPercent formats a percentage without trailing zeros, e.g. 93.5% or 100%.
+func formatPercent(percent float64) string {
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
+
+// loadCoverage returns the coverage history of a branch, or an empty history if nothing was uploaded.
+func (a *apiImpl) loadCoverage(project string, branchKey string) (coverageHistory, error) {
+	history := coverageHistory{}
+	record, err := a.coverageStore.Get(project, branchKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return history, nil
+	}
+	if err != nil {
+		return history, errors.Wrap(err, "loadCoverage")
+	}
+	if err := json.Unmarshal(record.Data, &history); err != nil {
+		return history, errors.Wrap(err, "loadCoverage")
+	}
+	return history, nil
+}
+
+// PostBadgeCoverageProject computes the coverage of a report and adds it to the history of the branch.
+func (a *apiImpl) PostBadgeCoverageProject(ctx echo.Context, project string, params PostBadgeCoverageProjectParams) error {
+	if err := a.pushAuthorize(ctx, project); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	branch, branchKey, err := a.projectBranch(project, params.Branch)
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	reportFormat, err := coverage.Parse(string(lo.FromPtr(params.ReportFormat)))
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusBadRequest, "Report format is invalid",
+			"invalid report format", err))
+	}
+
+	body, err := readBody(ctx, a.coverageConfig.MaxSize, "Coverage report")
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	report, err := coverage.Compute(reportFormat, body)
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusBadRequest, "Coverage report is invalid",
+			"invalid report", err))
+	}
+
+	// Uploads are serialized, across all branches, so concurrent uploads to a branch don't drop each other
+	// from the history.
+	a.coverageMtx.Lock()
+	defer a.coverageMtx.Unlock()
+
+	history, err := a.loadCoverage(project, branchKey)
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusInternalServerError,
+			"Coverage history could not be loaded", "store failed", err))
+	}
+
+	now := time.Now()
+	history.History = append(history.History, coverageEntry{
+		Lines:   report.Lines,
+		Covered: report.Covered,
+		Commit:  lo.FromPtr(params.Commit),
+		Updated: now,
+	})
+	if a.coverageConfig.History > 0 && len(history.History) > a.coverageConfig.History {
+		history.History = history.History[len(history.History)-a.coverageConfig.History:]
+	}
+
+	data, err := json.Marshal(&history)
+	if err != nil {
+		return errors.Wrap(err, "PostBadgeCoverageProject: BUG - coverage history could not be encoded")
+	}
+	if err := a.coverageStore.Put(project, branchKey, store.Record{Data: data, Updated: now}); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusInternalServerError,
+			"Coverage could not be stored", "store failed", err))
+	}
+
+	current, previous := history.latest()
+	result := CoverageResult{
+		Project: project,
+		Branch:  branch,
+		Lines:   current.Lines,
+		Covered: current.Covered,
+		Percent: current.Percent(),
+	}
+	if previous != nil {
+		result.Delta = lo.ToPtr(coverageDelta(current, *previous))
+	}
+	return ctx.JSON(http.StatusOK, &result)
+}
+
+// GetBadgeCoverageProject renders the coverage of the last upload to a branch.
+func (a *apiImpl) GetBadgeCoverageProject(ctx echo.Context, project string, params GetBadgeCoverageProjectParams) error {
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, errorLabel(params.Label, coverageDefaultLabel),
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	return a.errorResponse(ctx, opts, a.getCoverageBadge(ctx, project, params, opts))
+}
+
+func (a *apiImpl) getCoverageBadge(ctx echo.Context, project string, params GetBadgeCoverageProjectParams, opts renderOptions) error {
+	_, branchKey, err := a.projectBranch(project, params.Branch)
+	if err != nil {
+		return err
+	}
+
+	history, err := a.loadCoverage(project, branchKey)
+	if err != nil {
+		return newRequestError(http.StatusInternalServerError, "Coverage history could not be loaded", "store failed", err)
+	}
+	if len(history.History) == 0 {
+		return newRequestError(http.StatusNotFound, "No coverage has been uploaded for the branch", "no coverage",
+			ErrNoCoverage)
+	}
+
+	current, previous := history.latest()
+	percent := current.Percent()
+	coverageColor, _ := coverageColorRules.Color(strconv.FormatFloat(percent, 'f', -1, 64))
+
+	value := map[string]interface{}{
+		"coverage": formatPercent(percent),
+		"percent":  percent,
+		"lines":    current.Lines,
+		"covered":  current.Covered,
+		"commit":   current.Commit,
+		"updated":  current.Updated,
+		"delta":    "",
+	}
+	deltaColor := "lightgrey"
+	if previous != nil {
+		delta := coverageDelta(current, *previous)
+		value["delta"] = fmt.Sprintf("%s%s", lo.Ternary(delta >= 0, "+", ""), formatPercent(delta))
+		deltaColor = lo.Ternary(delta > 0, "brightgreen", lo.Ternary(delta < 0, "red", deltaColor))
+	}
+
+	segments := []segmentTemplate{{
+		Message: lo.FromPtrOr(params.Message, coverageDefaultMessage),
+		Color:   lo.FromPtrOr(params.Color, coverageColor),
+	}}
+	if lo.FromPtr(params.Delta) && previous != nil {
+		segments = append(segments, segmentTemplate{Message: coverageDeltaMessage, Color: deltaColor})
+	}
+
+	return a.getBadge(ctx, GetBadgeStaticParams{
+		Label:      lo.ToPtr(lo.FromPtrOr(params.Label, coverageDefaultLabel)),
+		LabelColor: params.LabelColor,
+	}, segments, map[string]interface{}{DynamicBadgeResponseName: value}, opts)
+}
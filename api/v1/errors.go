@@ -0,0 +1,124 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/badges"
+	"github.com/wrouesnel/badgeserv/pkg/pongo2utils"
+	"go.uber.org/zap"
+	"go.withmatt.com/httpheaders"
+)
+
+const (
+	// errorBadgeLabel is used on error badges when no label can be rendered.
+	errorBadgeLabel = "badge"
+	// errorBadgeColor is the color of error badges.
+	errorBadgeColor = "red"
+)
+
+// requestError is an error which is reported to the client, either as a ClientError or as an
+// error badge.
+type requestError struct {
+	Status      int
+	Description string
+	// Short is the message shown on error badges.
+	Short string
+	// Details lists the problems found if there are several.
+	Details []string
+	Err     error
+}
+
+func newRequestError(status int, description string, short string, err error) *requestError {
+	return &requestError{
+		Status:      status,
+		Description: description,
+		Short:       short,
+		Err:         err,
+	}
+}
+
+func (e *requestError) Error() string {
+	return errors.Wrap(e.Err, e.Description).Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.Err
+}
+
+// clientError returns the ClientError reported for the error.
+func (e *requestError) clientError() *ClientError {
+	clientErr := &ClientError{
+		Description: e.Description,
+		Error:       e.Err.Error(),
+	}
+	if len(e.Details) > 0 {
+		clientErr.Details = &e.Details
+	}
+	return clientErr
+}
+
+// acceptsJSON returns true if the client explicitly accepts application/json responses.
+func acceptsJSON(request *http.Request) bool {
+	for _, accepted := range strings.Split(request.Header.Get(httpheaders.Accept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == echo.MIMEApplicationJSON {
+			return true
+		}
+	}
+	return false
+}
+
+// errorLabel renders labelTemplate without any response data so error badges can keep the
+// label of the badge which failed. fallback is used if nothing is rendered.
+func errorLabel(labelTemplate *string, fallback string) string {
+	if tmpl, err := pongo2utils.FromStringUnescaped(lo.FromPtr(labelTemplate)); err == nil {
+		if label, err := tmpl.Execute(pongo2.Context{}); err == nil && strings.TrimSpace(label) != "" {
+			return label
+		}
+	}
+	return fallback
+}
+
+// errorResponse reports err to the client. Errors which are not a requestError are returned
+// unchanged. Clients which accept JSON always receive a ClientError, otherwise an error badge is
+// rendered if requested.
+func (a *apiImpl) errorResponse(ctx echo.Context, opts renderOptions, err error) error {
+	var reqErr *requestError
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+
+	if !opts.ErrorBadge || acceptsJSON(ctx.Request()) {
+		return ctx.JSON(reqErr.Status, reqErr.clientError())
+	}
+
+	label := lo.Ternary(opts.ErrorLabel != "", opts.ErrorLabel, errorBadgeLabel)
+	// An invalid style may be the error being reported, so fall back to the default style.
+	style := opts.Style
+	if _, err := badges.ParseStyle(style); err != nil {
+		style = ""
+	}
+	// An invalid scale may also be the error being reported.
+	if errors.Is(reqErr, badges.ErrInvalidScale) {
+		opts.Scale = 0
+	}
+	opts.IsError = true
+	// Error badges are served successfully so they display in pages which embed them.
+	badgeErr := a.writeBadge(ctx, badges.BadgeDesc{
+		Title: label,
+		Text:  "error: " + reqErr.Short,
+		Color: errorBadgeColor,
+		Style: style,
+	}, opts)
+	if badgeErr != nil {
+		a.logger.Error("Error badge generation failed", zap.Error(badgeErr))
+		return ctx.JSON(reqErr.Status, reqErr.clientError())
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/dataformat"
+	"github.com/wrouesnel/badgeserv/pkg/netfilter"
+	"go.uber.org/zap"
+	"go.withmatt.com/httpheaders"
+)
+
+var (
+	ErrTargetStatus           = errors.New("target responded with an error status")
+	ErrDynamicTargetsDisabled = errors.New("dynamic badge targets are disabled")
+)
+
+// maxRedirects is the number of redirects followed by target requests, as for the default http.Client.
+const maxRedirects = 10
+
+// targetHeadersKey is the context key of the names of the headers configured for a target request.
+type targetHeadersKey struct{}
+
+// TargetRedirectPolicy follows redirects of target requests. Headers configured for the target, such as
+// credentials, are only sent to the host of the target. They are dropped when a redirect leaves it, or
+// downgrades an https target to another scheme, so they are never sent elsewhere or in cleartext.
+func TargetRedirectPolicy() resty.RedirectPolicy {
+	return resty.RedirectPolicyFunc(func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		downgraded := strings.EqualFold(via[0].URL.Scheme, "https") && !strings.EqualFold(req.URL.Scheme, "https")
+		if strings.EqualFold(req.URL.Host, via[0].URL.Host) && !downgraded {
+			return nil
+		}
+		names, _ := req.Context().Value(targetHeadersKey{}).([]string)
+		for _, name := range names {
+			req.Header.Del(name)
+		}
+		return nil
+	})
+}
+
+// targetOptions configures how a badge target is requested.
+type targetOptions struct {
+	// Headers are sent with the target request.
+	Headers http.Header
+	// Format is the data format of the response. Auto or empty detects it from the Content-Type.
+	Format dataformat.Format
+	// CacheTTL overrides the default response cache TTL if not nil.
+	CacheTTL *time.Duration
+	// Trusted targets are configured by the operator rather than the client, and are requested with the trusted
+	// client, which bypasses the target filter.
+	Trusted bool
+}
+
+// cacheKey returns the key responses for target are cached and coalesced under. Responses are
+// cached decoded, so the format is part of the key. Requests with headers are keyed separately
+// so authenticated responses are never served to other requests.
+func (o targetOptions) cacheKey(target string) string {
+	key := target
+	if o.Format != "" && o.Format != dataformat.Auto {
+		key = fmt.Sprintf("%s#format=%s", key, o.Format)
+	}
+	// Responses of trusted targets may be from internal services, so they are never served to untrusted requests.
+	if o.Trusted {
+		key += "#trusted"
+	}
+	if len(o.Headers) == 0 {
+		return key
+	}
+
+	hash := sha256.New()
+	names := lo.Keys(o.Headers)
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range o.Headers[name] {
+			fmt.Fprintf(hash, "%s: %s\n", name, value)
+		}
+	}
+	return fmt.Sprintf("%s#headers=%x", key, hash.Sum(nil))
+}
+
+// cacheTTL returns how long responses for the target are cached: the CacheTTL of options, or the
+// cache default. Zero or less disables caching.
+func (a *apiImpl) cacheTTL(options targetOptions) time.Duration {
+	if options.CacheTTL != nil {
+		return *options.CacheTTL
+	}
+	return a.responseCache.DefaultTTL()
+}
+
+// fetchTarget retrieves and decodes the response from a dynamic badge target. Successful
+// responses are kept in the response cache for the CacheTTL of options, or the cache default.
+// Concurrent requests for the same target share a single upstream request.
+//
+// Expired responses are returned while a refresh happens in the background, and are also
+// returned if the target fails. stale is true whenever an expired response is returned. If
+// caching is disabled for the target, responses are neither cached nor served stale.
+func (a *apiImpl) fetchTarget(target string, options targetOptions) (responseData interface{}, stale bool, err error) {
+	key := options.cacheKey(target)
+	fetch := func() (interface{}, error) {
+		return a.fetchTargetUncached(target, key, options)
+	}
+
+	if a.cacheTTL(options) > 0 {
+		if responseData, ok := a.responseCache.Get(key); ok {
+			a.logger.Debug("Serving target response from cache", zap.String("target", target))
+			return responseData, false, nil
+		}
+
+		if staleData, ok := a.responseCache.GetStale(key); ok {
+			// A failed refresh leaves the stale entry in place, so it keeps being served while the target is down.
+			a.logger.Debug("Serving stale target response while revalidating", zap.String("target", target))
+			refresh := a.inflight.DoChan(key, fetch)
+			go func() {
+				if result := <-refresh; result.Err != nil {
+					a.logger.Warn("Background refresh of stale target failed", zap.String("target", target), zap.Error(result.Err))
+				}
+			}()
+			return staleData, true, nil
+		}
+	}
+
+	responseData, err, shared := a.inflight.Do(key, fetch)
+	if shared {
+		a.logger.Debug("Shared in-flight target request", zap.String("target", target))
+	}
+	return responseData, false, err //nolint:wrapcheck
+}
+
+// fetchTargetUncached makes the upstream request for fetchTarget and populates the cache.
+func (a *apiImpl) fetchTargetUncached(target string, key string, options targetOptions) (interface{}, error) {
+	a.logger.Debug("Making outbound request", zap.String("target", target))
+	ctx := context.WithValue(context.Background(), targetHeadersKey{}, lo.Keys(options.Headers))
+	client := lo.Ternary(options.Trusted, a.trustedClient, a.httpClient)
+	request := client.NewRequest().SetContext(ctx).SetHeaderMultiValues(options.Headers)
+	resp, err := request.Get(target)
+	if err != nil {
+		a.logger.Debug("Outbound HTTP request failed", zap.Error(err))
+		if errors.Is(err, netfilter.ErrDestinationDenied) {
+			return nil, newRequestError(http.StatusForbidden, "Target is not permitted", "target not permitted", err)
+		}
+		var netErr net.Error
+		short := lo.Ternary(errors.As(err, &netErr) && netErr.Timeout(), "upstream timeout", "upstream unreachable")
+		return nil, newRequestError(http.StatusBadGateway, "Target HTTP request failed", short, err)
+	}
+
+	if resp.IsError() {
+		return nil, newRequestError(http.StatusBadGateway, "Target returned an error status",
+			fmt.Sprintf("upstream status %d", resp.StatusCode()), errors.Wrap(ErrTargetStatus, resp.Status()))
+	}
+
+	var responseData interface{}
+	format := options.Format
+	if format == "" || format == dataformat.Auto {
+		responseData, format, err = dataformat.DecodeAuto(resp.Header().Get(httpheaders.ContentType), resp.Body())
+	} else {
+		responseData, err = dataformat.Decode(format, resp.Body())
+	}
+	if err != nil {
+		return nil, newRequestError(http.StatusBadGateway, fmt.Sprintf("Response could not be decoded as %s", format),
+			"invalid upstream response", err)
+	}
+
+	a.responseCache.Set(key, responseData, a.cacheTTL(options))
+
+	return responseData, nil
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestTargetRedirectPolicy(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if location := r.URL.Query().Get("to"); location != "" {
+			http.Redirect(w, r, location, http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Private-Token") + "," + r.Header.Get("X-Other")))
+	}))
+	t.Cleanup(server.Close)
+	// The server is reached under a different host by its name rather than its IP address.
+	otherHost := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"no redirect", server.URL, "token,other"},
+		{"same host", server.URL + "?to=" + server.URL + "/badge", "token,other"},
+		{"other host", server.URL + "?to=" + otherHost + "/badge", ",other"},
+		{"back to the target host", server.URL + "?to=" + otherHost + "/%3Fto=" + server.URL + "/badge", "token,other"},
+	}
+
+	client := resty.New().SetRedirectPolicy(TargetRedirectPolicy())
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.WithValue(context.Background(), targetHeadersKey{}, []string{"Private-Token"})
+			resp, err := client.R().SetContext(ctx).
+				SetHeader("Private-Token", "token").SetHeader("X-Other", "other").Get(test.target)
+			if err != nil {
+				t.Fatalf("GET %s: %v", test.target, err)
+			}
+			if got := resp.String(); got != test.want {
+				t.Errorf("GET %s = %q, want %q", test.target, got, test.want)
+			}
+		})
+	}
+}
+
+func TestTargetRedirectPolicyHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		original string
+		redirect string
+		kept     bool
+	}{
+		{"same origin", "https://example.com/a", "https://example.com/b", true},
+		{"upgrade", "http://example.com/a", "https://example.com/b", true},
+		{"downgrade", "https://example.com/a", "http://example.com/b", false},
+		{"other port", "https://example.com/a", "https://example.com:8443/b", false},
+		{"other host", "https://example.com/a", "https://example.org/b", false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.WithValue(context.Background(), targetHeadersKey{}, []string{"Authorization"})
+			original := httptest.NewRequest(http.MethodGet, test.original, nil).WithContext(ctx)
+			redirect := httptest.NewRequest(http.MethodGet, test.redirect, nil).WithContext(ctx)
+			redirect.Header.Set("Authorization", "Bearer token")
+			redirect.Header.Set("X-Other", "other")
+
+			if err := TargetRedirectPolicy().Apply(redirect, []*http.Request{original}); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if kept := redirect.Header.Get("Authorization") != ""; kept != test.kept {
+				t.Errorf("Authorization kept = %v, want %v", kept, test.kept)
+			}
+			if redirect.Header.Get("X-Other") == "" {
+				t.Errorf("unconfigured header was dropped")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/badges"
+	"go.withmatt.com/httpheaders"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown badge format")
+)
+
+const (
+	formatSVG  = "svg"
+	formatPNG  = "png"
+	formatJSON = "json"
+	// segmentSeparator joins the segments of multi-segment badges in JSON badges.
+	segmentSeparator = " | "
+
+	// pngSuffix requests a PNG badge when added to a badge path.
+	pngSuffix = ".png"
+	// mimeImagePNG and mimeImageSVG are the content types of badges.
+	mimeImagePNG = "image/png"
+	mimeImageSVG = "image/svg+xml"
+)
+
+// negotiateFormat returns the format of the badge. An explicit format parameter takes precedence,
+// otherwise PNG or JSON is returned to clients which accept image/png or application/json but not
+// image/svg+xml.
+func negotiateFormat(request *http.Request, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case formatSVG:
+		return formatSVG, nil
+	case formatPNG:
+		return formatPNG, nil
+	case formatJSON:
+		return formatJSON, nil
+	case "":
+	default:
+		return formatSVG, newRequestError(http.StatusBadRequest, "Badge format is invalid", "invalid format",
+			errors.Wrapf(ErrUnknownFormat, "%q", format))
+	}
+
+	acceptsPNG, acceptsSVG, acceptsJSON := false, false, false
+	for _, accepted := range strings.Split(request.Header.Get(httpheaders.Accept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+		acceptsPNG = acceptsPNG || mediaType == mimeImagePNG
+		acceptsSVG = acceptsSVG || mediaType == mimeImageSVG
+		acceptsJSON = acceptsJSON || mediaType == echo.MIMEApplicationJSON
+	}
+	switch {
+	case acceptsSVG:
+		return formatSVG, nil
+	case acceptsPNG:
+		return formatPNG, nil
+	case acceptsJSON:
+		return formatJSON, nil
+	default:
+		return formatSVG, nil
+	}
+}
+
+// PNGSuffixMiddleware rewrites requests for badge paths ending in ".png" to request a PNG badge
+// from the badge endpoint, so badges can be linked as image files. badgePath is the path prefix of
+// the badge endpoints.
+func PNGSuffixMiddleware(badgePath string) echo.MiddlewareFunc {
+	// The predefined badge listing is not a badge, so is never rewritten.
+	listingPath := badgePath + "/predefined"
+	predefinedPath := listingPath + "/"
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+			if !strings.HasPrefix(request.URL.Path, badgePath+"/") || !strings.HasSuffix(request.URL.Path, pngSuffix) ||
+				request.URL.Path == listingPath+pngSuffix {
+				return next(ctx)
+			}
+
+			stripSuffix := func(path string) string {
+				path = strings.TrimSuffix(path, pngSuffix)
+				// Predefined badge paths end in a slash, which is dropped before the suffix.
+				if strings.HasPrefix(path, predefinedPath) && !strings.HasSuffix(path, "/") {
+					path += "/"
+				}
+				return path
+			}
+			request.URL.Path = stripSuffix(request.URL.Path)
+			if request.URL.RawPath != "" {
+				request.URL.RawPath = stripSuffix(request.URL.RawPath)
+			}
+
+			query := request.URL.Query()
+			query.Set("format", formatPNG)
+			request.URL.RawQuery = query.Encode()
+
+			return next(ctx)
+		}
+	}
+}
+
+// writeBadge renders the badge in the requested format and writes the response.
+func (a *apiImpl) writeBadge(ctx echo.Context, desc badges.BadgeDesc, opts renderOptions) error {
+	// The format may be negotiated from the Accept header.
+	ctx.Response().Header().Add(httpheaders.Vary, httpheaders.Accept)
+	cacheControl := "no-cache"
+	if opts.MaxAge > 0 {
+		cacheControl = fmt.Sprintf("max-age=%d", int(opts.MaxAge.Seconds()))
+	}
+
+	if opts.Format == formatJSON {
+		return a.endpointResponse(ctx, a.badgeService.ResolveColors(desc), opts, cacheControl)
+	}
+
+	if opts.Format == formatPNG {
+		badge, err := a.badgeService.CreateBadgePNG(desc, opts.Scale)
+		if err != nil {
+			return errors.Wrap(err, "writeBadge")
+		}
+		return a.pngResponse(ctx, badge, cacheControl)
+	}
+
+	badge, err := a.badgeService.CreateBadge(desc)
+	if err != nil {
+		return errors.Wrap(err, "writeBadge")
+	}
+	return a.svgResponse(ctx, badge, cacheControl)
+}
+
+func (a *apiImpl) pngResponse(ctx echo.Context, pngData []byte, cacheControl string) error {
+	ctx.Response().Header().Set(httpheaders.Etag, a.generateETag(pngData))
+	ctx.Response().Header().Set(httpheaders.CacheControl, cacheControl)
+	return ctx.Blob(http.StatusOK, mimeImagePNG, pngData)
+}
+
+// endpointResponse writes the text and colors of a badge in the shields.io endpoint schema.
+func (a *apiImpl) endpointResponse(ctx echo.Context, desc badges.BadgeDesc, opts renderOptions, cacheControl string) error {
+	message, color := desc.Text, desc.Color
+	if len(desc.Segments) > 0 {
+		message = strings.Join(lo.Map(desc.Segments, func(segment badges.Segment, _ int) string {
+			return segment.Text
+		}), segmentSeparator)
+		color = desc.Segments[0].Color
+	}
+
+	response := EndpointBadge{
+		SchemaVersion: shieldsSchemaVersion,
+		Label:         desc.Title,
+		Message:       message,
+		Color:         color,
+		LabelColor:    desc.LabelColor,
+		IsError:       opts.IsError,
+	}
+	if cacheFor := lo.Ternary(opts.MaxAge > 0, opts.MaxAge, opts.CacheTTL); cacheFor > 0 {
+		response.CacheSeconds = lo.ToPtr(int(cacheFor.Seconds()))
+	}
+
+	ctx.Response().Header().Set(httpheaders.CacheControl, cacheControl)
+	return ctx.JSON(http.StatusOK, &response)
+}
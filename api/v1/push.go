@@ -0,0 +1,184 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/store"
+)
+
+var (
+	ErrPushTokenMissing = errors.New("Authorization header must contain a bearer token")
+	ErrPushTokenInvalid = errors.New("push token is not valid for the namespace")
+	ErrPushDisabled     = errors.New("no push token is configured for the namespace")
+	ErrRequestTooLarge  = errors.New("request body is too large")
+	ErrPushValueInvalid = errors.New("pushed value must be JSON")
+	ErrInvalidBranch    = errors.New("branch names must start with a letter or digit")
+)
+
+const (
+	pushDefaultLabel      = "{{ r.label }}"
+	pushDefaultMessage    = "{{ r.message }}"
+	pushDefaultColor      = "{{ r.color }}"
+	pushDefaultLabelColor = "{{ r.labelColor }}"
+	// pushValueMessage is the default message of pushed values which aren't objects.
+	pushValueMessage = "{{ r }}"
+)
+
+// PushConfig configures the badge push API.
+type PushConfig struct {
+	Tokens  map[string]string `help:"Bearer token which may push badges to each namespace (namespace=token)"`
+	MaxSize int64             `help:"Maximum size of a pushed value in bytes" default:"65536"`
+	Store   store.Config      `embed:"" prefix:"store."`
+
+	DefaultBranch string `help:"Branch uploaded reports are stored for and shown when no branch is given" default:"main"`
+}
+
+// StoreTrailingSlashMiddleware removes a trailing slash from the paths of pushed, coverage and test results
+// badges, whose last path parameter would otherwise include it. badgePath is the path prefix of the badge
+// endpoints.
+func StoreTrailingSlashMiddleware(badgePath string) echo.MiddlewareFunc {
+	prefixes := []string{badgePath + "/pushed/", badgePath + "/coverage/", badgePath + "/tests/"}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+			if !strings.HasSuffix(request.URL.Path, "/") || !lo.SomeBy(prefixes, func(prefix string) bool {
+				return strings.HasPrefix(request.URL.Path, prefix)
+			}) {
+				return next(ctx)
+			}
+
+			request.URL.Path = strings.TrimRight(request.URL.Path, "/")
+			if request.URL.RawPath != "" {
+				request.URL.RawPath = strings.TrimRight(request.URL.RawPath, "/")
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// pushAuthorize checks the bearer token of the request against the token of the namespace.
+func (a *apiImpl) pushAuthorize(ctx echo.Context, namespace string) error {
+	expected, ok := a.pushConfig.Tokens[namespace]
+	if !ok || expected == "" {
+		return newRequestError(http.StatusForbidden, "Pushing to the namespace is not enabled",
+			"push disabled", ErrPushDisabled)
+	}
+
+	scheme, token, found := strings.Cut(ctx.Request().Header.Get(echo.HeaderAuthorization), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+		return newRequestError(http.StatusUnauthorized, "Push token is missing", "unauthorized", ErrPushTokenMissing)
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+		return newRequestError(http.StatusUnauthorized, "Push token is invalid", "unauthorized", ErrPushTokenInvalid)
+	}
+	return nil
+}
+
+// projectBranch returns the branch requested and its key in the store of project, for reports
+// uploaded per branch.
+func (a *apiImpl) projectBranch(project string, branch *string) (string, string, error) {
+	name := lo.Ternary(lo.FromPtr(branch) != "", lo.FromPtr(branch), a.pushConfig.DefaultBranch)
+	key := store.EscapeKey(name)
+	if err := store.ValidateKey(key, key); err != nil {
+		return name, key, newRequestError(http.StatusBadRequest, "Branch name is invalid", "invalid branch",
+			errors.Wrapf(ErrInvalidBranch, "%q", name))
+	}
+	if err := store.ValidateKey(project, key); err != nil {
+		return name, key, newRequestError(http.StatusBadRequest, "Project name is invalid", "invalid project", err)
+	}
+	return name, key, nil
+}
+
+// readBody reads a request body of up to limit bytes. what names the body in errors.
+func readBody(ctx echo.Context, limit int64, what string) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(ctx.Response(), ctx.Request().Body, limit))
+	if err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			return nil, newRequestError(http.StatusRequestEntityTooLarge, fmt.Sprintf("%s is too large", what),
+				"too large", errors.Wrapf(ErrRequestTooLarge, "limit is %d bytes", limit))
+		}
+		return nil, newRequestError(http.StatusBadRequest, fmt.Sprintf("%s could not be read", what),
+			"invalid request", err)
+	}
+	return body, nil
+}
+
+// PutBadgePushedNamespaceName stores the pushed value of a badge.
+func (a *apiImpl) PutBadgePushedNamespaceName(ctx echo.Context, namespace string, name string) error {
+	if err := a.pushAuthorize(ctx, namespace); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	if err := store.ValidateKey(namespace, name); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusBadRequest, "Badge name is invalid",
+			"invalid name", err))
+	}
+
+	body, err := readBody(ctx, a.pushConfig.MaxSize, "Pushed value")
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+
+	if !json.Valid(body) {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusBadRequest, "Pushed value is invalid",
+			"invalid value", ErrPushValueInvalid))
+	}
+
+	if err := a.pushStore.Put(namespace, name, store.Record{Data: body, Updated: time.Now()}); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusInternalServerError,
+			"Pushed value could not be stored", "store failed", err))
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetBadgePushedNamespaceName renders a badge from the value last pushed to it.
+func (a *apiImpl) GetBadgePushedNamespaceName(ctx echo.Context, namespace string, name string, params GetBadgePushedNamespaceNameParams) error {
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, errorLabel(params.Label, name),
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	return a.errorResponse(ctx, opts, a.getPushedBadge(ctx, namespace, name, params, opts))
+}
+
+func (a *apiImpl) getPushedBadge(ctx echo.Context, namespace string, name string, params GetBadgePushedNamespaceNameParams, opts renderOptions) error {
+	record, err := a.pushStore.Get(namespace, name)
+	if errors.Is(err, store.ErrNotFound) {
+		return newRequestError(http.StatusNotFound, "Nothing has been pushed to the badge", "badge not found", err)
+	}
+	if errors.Is(err, store.ErrInvalidKey) {
+		return newRequestError(http.StatusBadRequest, "Badge name is invalid", "invalid name", err)
+	}
+	if err != nil {
+		return newRequestError(http.StatusInternalServerError, "Pushed value could not be loaded", "store failed", err)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(record.Data, &value); err != nil {
+		return newRequestError(http.StatusInternalServerError, "Pushed value could not be loaded", "store failed", err)
+	}
+
+	templateCtx := map[string]interface{}{}
+	templateCtx[DynamicBadgeResponseName] = value
+
+	// Values which aren't objects have no fields, so are shown as the message by default.
+	_, isObject := value.(map[string]interface{})
+	return a.getBadge(ctx, GetBadgeStaticParams{
+		Label:      lo.ToPtr(lo.FromPtrOr(params.Label, lo.Ternary(isObject, pushDefaultLabel, ""))),
+		Message:    lo.ToPtr(lo.FromPtrOr(params.Message, lo.Ternary(isObject, pushDefaultMessage, pushValueMessage))),
+		Color:      lo.ToPtr(lo.FromPtrOr(params.Color, lo.Ternary(isObject, pushDefaultColor, ""))),
+		LabelColor: lo.ToPtr(lo.FromPtrOr(params.LabelColor, lo.Ternary(isObject, pushDefaultLabelColor, ""))),
+	}, nil, templateCtx, opts)
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestStoreTrailingSlashMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/badge/pushed/proj/version/", "/api/v1/badge/pushed/proj/version"},
+		{"/api/v1/badge/pushed/proj/version", "/api/v1/badge/pushed/proj/version"},
+		{"/api/v1/badge/coverage/proj//", "/api/v1/badge/coverage/proj"},
+		{"/api/v1/badge/tests/proj/", "/api/v1/badge/tests/proj"},
+		{"/api/v1/badge/predefined/name/", "/api/v1/badge/predefined/name/"},
+		{"/api/v1/badge/static/", "/api/v1/badge/static/"},
+	}
+
+	middleware := StoreTrailingSlashMiddleware("/api/v1/badge")
+	for _, test := range tests {
+		test := test
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+			ctx := echo.New().NewContext(httptest.NewRequest(http.MethodGet, test.path, nil), httptest.NewRecorder())
+			err := middleware(func(ctx echo.Context) error {
+				if got := ctx.Request().URL.Path; got != test.want {
+					t.Errorf("path = %q, want %q", got, test.want)
+				}
+				return nil
+			})(ctx)
+			if err != nil {
+				t.Fatalf("middleware: %v", err)
+			}
+		})
+	}
+}
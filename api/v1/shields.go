@@ -0,0 +1,270 @@
+package api
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/badges"
+	"github.com/wrouesnel/badgeserv/pkg/dataformat"
+)
+
+var (
+	ErrInvalidShieldsBadge     = errors.New("badge path must be label-message-color or message-color")
+	ErrMissingEndpointURL      = errors.New("url parameter is required")
+	ErrInvalidEndpointResponse = errors.New("invalid endpoint response")
+)
+
+// shieldsSchemaVersion is the supported version of the shields.io endpoint schema.
+const shieldsSchemaVersion = 1
+
+// ShieldsInterface implements routes compatible with shields.io badge URLs, so existing badge URLs only need
+// their host changed.
+type ShieldsInterface interface {
+	// GetShieldsBadge renders a static badge from a shields.io badge path such as label-message-color.
+	GetShieldsBadge(ctx echo.Context, spec string) error
+	// GetShieldsEndpoint renders a badge from a URL serving the shields.io endpoint schema.
+	GetShieldsEndpoint(ctx echo.Context) error
+}
+
+// RegisterShieldsHandlers adds the shields.io compatible routes under prefix.
+func RegisterShieldsHandlers(router EchoRouter, si ShieldsInterface, prefix string) {
+	badgePath := prefix + "/badge/"
+	router.GET(badgePath+"*", func(ctx echo.Context) error {
+		// The decoded path is used since badge text can contain escaped slashes.
+		return si.GetShieldsBadge(ctx, strings.TrimPrefix(ctx.Request().URL.Path, badgePath))
+	})
+	router.GET(prefix+"/endpoint", si.GetShieldsEndpoint)
+}
+
+// shieldsEndpoint is the response of a shields.io endpoint badge URL.
+type shieldsEndpoint struct {
+	SchemaVersion int     `mapstructure:"schemaVersion"`
+	Label         string  `mapstructure:"label"`
+	Message       *string `mapstructure:"message"`
+	Color         string  `mapstructure:"color"`
+	LabelColor    string  `mapstructure:"labelColor"`
+	IsError       bool    `mapstructure:"isError"`
+	NamedLogo     string  `mapstructure:"namedLogo"`
+	LogoSvg       string  `mapstructure:"logoSvg"`
+	LogoColor     string  `mapstructure:"logoColor"`
+	LogoWidth     int     `mapstructure:"logoWidth"`
+	Style         string  `mapstructure:"style"`
+	CacheSeconds  int     `mapstructure:"cacheSeconds"`
+}
+
+// parseShieldsBadge splits a shields.io badge path into its label, message and color. Fields are separated by
+// single dashes, "--" is a dash, "__" is an underscore and "_" is a space. The label is optional.
+func parseShieldsBadge(spec string) (string, string, string, error) {
+	fields := []string{}
+	field := strings.Builder{}
+	for idx := 0; idx < len(spec); idx++ {
+		switch {
+		case strings.HasPrefix(spec[idx:], "--"):
+			field.WriteByte('-')
+			idx++
+		case strings.HasPrefix(spec[idx:], "__"):
+			field.WriteByte('_')
+			idx++
+		case spec[idx] == '-':
+			fields = append(fields, field.String())
+			field.Reset()
+		case spec[idx] == '_':
+			field.WriteByte(' ')
+		default:
+			field.WriteByte(spec[idx])
+		}
+	}
+	fields = append(fields, field.String())
+
+	switch len(fields) {
+	case 2: //nolint:gomnd
+		return "", fields[0], fields[1], nil
+	case 3: //nolint:gomnd
+		return fields[0], fields[1], fields[2], nil
+	default:
+		return "", "", "", errors.Wrapf(ErrInvalidShieldsBadge, "%q", spec)
+	}
+}
+
+// shieldsOptions reads the render options shields.io badges accept as query parameters.
+func (a *apiImpl) shieldsOptions(query url.Values, format string) (renderOptions, error) {
+	opts := renderOptions{
+		ErrorBadge: a.errorBadges,
+		ErrorLabel: lo.Ternary(query.Get("label") != "", query.Get("label"), errorBadgeLabel),
+		Style:      query.Get("style"),
+		Logo:       query.Get("logo"),
+		LogoColor:  query.Get("logoColor"),
+		Format:     format,
+	}
+
+	var err error
+	if value := query.Get("errorBadge"); value != "" {
+		if opts.ErrorBadge, err = strconv.ParseBool(value); err != nil {
+			return opts, newRequestError(http.StatusBadRequest, "errorBadge must be true or false", "invalid errorBadge", err)
+		}
+	}
+	if value := query.Get("logoWidth"); value != "" {
+		if opts.LogoWidth, err = strconv.Atoi(value); err != nil {
+			return opts, newRequestError(http.StatusBadRequest, "Badge logo is invalid", "invalid logo", err)
+		}
+	}
+	if value := query.Get("scale"); value != "" {
+		if opts.Scale, err = strconv.ParseFloat(value, 64); err != nil {
+			return opts, newRequestError(http.StatusBadRequest, "Badge scale is invalid", "invalid scale", err)
+		}
+	}
+	if value := query.Get("cacheSeconds"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return opts, newRequestError(http.StatusBadRequest, "cacheSeconds must be a number of seconds",
+				"invalid cacheSeconds", err)
+		}
+		opts.MaxAge = time.Duration(seconds) * time.Second
+	}
+	return opts, nil
+}
+
+// GetShieldsBadge implements shields.io static badge paths, e.g. /badge/build-passing-green.svg.
+func (a *apiImpl) GetShieldsBadge(ctx echo.Context, spec string) error {
+	query := ctx.QueryParams()
+
+	// The extension selects the format, as the format parameter does.
+	requestedFormat := query.Get("format")
+	for _, ext := range []string{formatSVG, formatPNG, formatJSON} {
+		if strings.HasSuffix(spec, "."+ext) {
+			spec, requestedFormat = strings.TrimSuffix(spec, "."+ext), ext
+		}
+	}
+
+	format, formatErr := negotiateFormat(ctx.Request(), requestedFormat)
+	opts, optsErr := a.shieldsOptions(query, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	if optsErr != nil {
+		return a.errorResponse(ctx, opts, optsErr)
+	}
+
+	label, message, color, err := parseShieldsBadge(spec)
+	if err != nil {
+		return a.errorResponse(ctx, opts, newRequestError(http.StatusBadRequest, "Badge path is invalid", "invalid badge", err))
+	}
+	if !query.Has("label") && label != "" {
+		opts.ErrorLabel = label
+	}
+
+	return a.errorResponse(ctx, opts, a.renderBadge(ctx, badges.BadgeDesc{
+		Title:      lo.Ternary(query.Has("label"), query.Get("label"), label),
+		Text:       message,
+		Color:      lo.Ternary(query.Has("color"), query.Get("color"), color),
+		LabelColor: query.Get("labelColor"),
+		Style:      opts.Style,
+		Logo:       opts.Logo,
+		LogoColor:  opts.LogoColor,
+		LogoWidth:  opts.LogoWidth,
+		NoLabel:    true,
+	}, opts))
+}
+
+// GetShieldsEndpoint implements shields.io endpoint badges, e.g. /endpoint?url=https://example.com/badge.json.
+func (a *apiImpl) GetShieldsEndpoint(ctx echo.Context) error {
+	query := ctx.QueryParams()
+	format, formatErr := negotiateFormat(ctx.Request(), query.Get("format"))
+	opts, optsErr := a.shieldsOptions(query, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	if optsErr != nil {
+		return a.errorResponse(ctx, opts, optsErr)
+	}
+	if a.dynamicDisabled {
+		return a.errorResponse(ctx, opts, newRequestError(http.StatusForbidden, "Dynamic badge targets are disabled",
+			"dynamic badges disabled", ErrDynamicTargetsDisabled))
+	}
+	return a.errorResponse(ctx, opts, a.getShieldsEndpoint(ctx, query, opts))
+}
+
+func (a *apiImpl) getShieldsEndpoint(ctx echo.Context, query url.Values, opts renderOptions) error {
+	target := query.Get("url")
+	if target == "" {
+		return newRequestError(http.StatusBadRequest, "Endpoint URL is missing", "missing url", ErrMissingEndpointURL)
+	}
+
+	responseData, stale, err := a.fetchTarget(target, targetOptions{Format: dataformat.JSON})
+	if err != nil {
+		return err
+	}
+
+	endpoint := shieldsEndpoint{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{WeaklyTypedInput: true, Result: &endpoint})
+	if err != nil {
+		return errors.Wrap(err, "getShieldsEndpoint: BUG - decoder configuration rejected")
+	}
+	if err := decoder.Decode(responseData); err != nil {
+		return newRequestError(http.StatusBadGateway, "Endpoint response does not match the endpoint schema",
+			"invalid endpoint response", errors.Wrap(ErrInvalidEndpointResponse, err.Error()))
+	}
+	if endpoint.SchemaVersion != shieldsSchemaVersion {
+		return newRequestError(http.StatusBadGateway, "Endpoint response does not match the endpoint schema",
+			"invalid endpoint response", errors.Wrapf(ErrInvalidEndpointResponse, "unsupported schemaVersion %d", endpoint.SchemaVersion))
+	}
+	if endpoint.Message == nil {
+		return newRequestError(http.StatusBadGateway, "Endpoint response does not match the endpoint schema",
+			"invalid endpoint response", errors.Wrap(ErrInvalidEndpointResponse, "message is required"))
+	}
+
+	desc := badges.BadgeDesc{
+		Title:      endpoint.Label,
+		Text:       *endpoint.Message,
+		Color:      lo.Ternary(endpoint.Color == "" && endpoint.IsError, errorBadgeColor, endpoint.Color),
+		LabelColor: endpoint.LabelColor,
+		Style:      endpoint.Style,
+		Logo:       endpoint.NamedLogo,
+		LogoColor:  endpoint.LogoColor,
+		LogoWidth:  endpoint.LogoWidth,
+	}
+	if endpoint.LogoSvg != "" {
+		desc.Logo = fmt.Sprintf("data:image/svg+xml;base64,%s", base64.StdEncoding.EncodeToString([]byte(endpoint.LogoSvg)))
+	}
+
+	// Query parameters override the endpoint response.
+	for param, value := range map[string]*string{
+		"label":      &desc.Title,
+		"color":      &desc.Color,
+		"labelColor": &desc.LabelColor,
+	} {
+		if query.Has(param) {
+			*value = query.Get(param)
+		}
+	}
+	desc.Style = lo.Ternary(opts.Style != "", opts.Style, desc.Style)
+	desc.Logo = lo.Ternary(opts.Logo != "", opts.Logo, desc.Logo)
+	desc.LogoColor = lo.Ternary(opts.LogoColor != "", opts.LogoColor, desc.LogoColor)
+	desc.LogoWidth = lo.Ternary(opts.LogoWidth != 0, opts.LogoWidth, desc.LogoWidth)
+	opts.IsError = endpoint.IsError
+	if opts.MaxAge == 0 && endpoint.CacheSeconds > 0 && !endpoint.IsError {
+		opts.MaxAge = time.Duration(endpoint.CacheSeconds) * time.Second
+	}
+
+	if stale {
+		desc.Text += a.staleConfig.Suffix
+		desc.Color = lo.Ternary(a.staleConfig.Color != "", a.staleConfig.Color, desc.Color)
+	}
+
+	err = a.renderBadge(ctx, desc, opts)
+	// Endpoints may name shields.io logos which aren't available here, so the badge is drawn without them.
+	if errors.Is(err, badges.ErrUnknownLogo) && opts.Logo == "" {
+		desc.Logo = ""
+		err = a.renderBadge(ctx, desc, opts)
+	}
+	return err
+}
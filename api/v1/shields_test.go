@@ -0,0 +1,51 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseShieldsBadge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		label   string
+		message string
+		color   string
+		err     error
+	}{
+		{spec: "build-passing-green", label: "build", message: "passing", color: "green"},
+		{spec: "passing-green", message: "passing", color: "green"},
+		{spec: "just_the_message-blue", message: "just the message", color: "blue"},
+		{spec: "snake__case-value-red", label: "snake_case", message: "value", color: "red"},
+		{spec: "dash--ed-1--2-orange", label: "dash-ed", message: "1-2", color: "orange"},
+		{spec: "a---b-c", label: "a-", message: "b", color: "c"},
+		{spec: "-message-green", message: "message", color: "green"},
+		{spec: "__-___-grey", label: "_", message: "_ ", color: "grey"},
+		{spec: "only", err: ErrInvalidShieldsBadge},
+		{spec: "too-many-dash-es", err: ErrInvalidShieldsBadge},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.spec, func(t *testing.T) {
+			t.Parallel()
+			label, message, color, err := parseShieldsBadge(test.spec)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("parseShieldsBadge(%q) error = %v, want %v", test.spec, err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseShieldsBadge(%q): %v", test.spec, err)
+			}
+			if label != test.label || message != test.message || color != test.color {
+				t.Errorf("parseShieldsBadge(%q) = %q, %q, %q, want %q, %q, %q", test.spec,
+					label, message, color, test.label, test.message, test.color)
+			}
+		})
+	}
+}
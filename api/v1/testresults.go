@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/store"
+	"github.com/wrouesnel/badgeserv/pkg/testresults"
+)
+
+var (
+	ErrNoTestResults = errors.New("no test report has been uploaded")
+)
+
+const (
+	testsDefaultLabel   = "tests"
+	testsDefaultMessage = "{{ r.summary }}"
+)
+
+// TestsConfig configures test report uploads.
+type TestsConfig struct {
+	MaxSize int64 `help:"Maximum size of an uploaded test report in bytes" default:"33554432"`
+}
+
+// testsSummary formats test results as e.g. "412 passed, 3 failed, 5 skipped". Failed and skipped
+// tests are left out if there are none.
+func testsSummary(results TestResults) string {
+	parts := []string{fmt.Sprintf("%d passed", results.Passed)}
+	if results.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", results.Failed))
+	}
+	if results.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", results.Skipped))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// testsColor returns the badge color for test results: red if any test failed, green if any passed
+// and grey if every test was skipped.
+func testsColor(results TestResults) string {
+	switch {
+	case results.Failed > 0:
+		return "red"
+	case results.Passed > 0:
+		return "brightgreen"
+	default:
+		return "lightgrey"
+	}
+}
+
+// PostBadgeTestsProject stores the results of a test report for the branch.
+func (a *apiImpl) PostBadgeTestsProject(ctx echo.Context, project string, params PostBadgeTestsProjectParams) error {
+	if err := a.pushAuthorize(ctx, project); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	branch, branchKey, err := a.projectBranch(project, params.Branch)
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	reportFormat, err := testresults.Parse(string(lo.FromPtr(params.ReportFormat)))
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusBadRequest, "Report format is invalid",
+			"invalid report format", err))
+	}
+
+	body, err := readBody(ctx, a.testsConfig.MaxSize, "Test report")
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, err)
+	}
+	summary, err := testresults.Compute(reportFormat, body)
+	if err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusBadRequest, "Test report is invalid",
+			"invalid report", err))
+	}
+
+	now := time.Now()
+	results := TestResults{
+		Project: project,
+		Branch:  branch,
+		Passed:  summary.Passed,
+		Failed:  summary.Failed,
+		Skipped: summary.Skipped,
+		Total:   summary.Total(),
+		Commit:  params.Commit,
+		Updated: &now,
+	}
+	data, err := json.Marshal(&results)
+	if err != nil {
+		return errors.Wrap(err, "PostBadgeTestsProject: BUG - test results could not be encoded")
+	}
+	if err := a.testsStore.Put(project, branchKey, store.Record{Data: data, Updated: now}); err != nil {
+		return a.errorResponse(ctx, renderOptions{}, newRequestError(http.StatusInternalServerError,
+			"Test results could not be stored", "store failed", err))
+	}
+	return ctx.JSON(http.StatusOK, &results)
+}
+
+// GetBadgeTestsProject renders the results of the last test report uploaded to a branch.
+func (a *apiImpl) GetBadgeTestsProject(ctx echo.Context, project string, params GetBadgeTestsProjectParams) error {
+	format, formatErr := negotiateFormat(ctx.Request(), string(lo.FromPtr(params.Format)))
+	opts := a.renderOptionsFrom(params.ErrorBadge, errorLabel(params.Label, testsDefaultLabel),
+		string(lo.FromPtr(params.Style)), params.Logo, params.LogoColor, params.LogoWidth, params.Scale, format)
+	if formatErr != nil {
+		return a.errorResponse(ctx, opts, formatErr)
+	}
+	return a.errorResponse(ctx, opts, a.getTestsBadge(ctx, project, params, opts))
+}
+
+func (a *apiImpl) getTestsBadge(ctx echo.Context, project string, params GetBadgeTestsProjectParams, opts renderOptions) error {
+	_, branchKey, err := a.projectBranch(project, params.Branch)
+	if err != nil {
+		return err
+	}
+
+	record, err := a.testsStore.Get(project, branchKey)
+	if errors.Is(err, store.ErrNotFound) {
+		return newRequestError(http.StatusNotFound, "No test report has been uploaded for the branch", "no test results",
+			ErrNoTestResults)
+	}
+	if err != nil {
+		return newRequestError(http.StatusInternalServerError, "Test results could not be loaded", "store failed", err)
+	}
+	results := TestResults{}
+	if err := json.Unmarshal(record.Data, &results); err != nil {
+		return newRequestError(http.StatusInternalServerError, "Test results could not be loaded", "store failed", err)
+	}
+
+	value := map[string]interface{}{
+		"summary": testsSummary(results),
+		"passed":  results.Passed,
+		"failed":  results.Failed,
+		"skipped": results.Skipped,
+		"total":   results.Total,
+		"commit":  lo.FromPtr(results.Commit),
+	}
+
+	return a.getBadge(ctx, GetBadgeStaticParams{
+		Label:      lo.ToPtr(lo.FromPtrOr(params.Label, testsDefaultLabel)),
+		Message:    lo.ToPtr(lo.FromPtrOr(params.Message, testsDefaultMessage)),
+		Color:      lo.ToPtr(lo.FromPtrOr(params.Color, testsColor(results))),
+		LabelColor: params.LabelColor,
+	}, nil, map[string]interface{}{DynamicBadgeResponseName: value}, opts)
+}
@@ -8,7 +8,7 @@ import (
 
 const filesystemAssetPath = "assets"
 
-//go:embed swagger-ui fonts badges web
+//go:embed swagger-ui fonts badges icons web
 var assets embed.FS
 
 // Assets returns an fs.FS object pointing to the asset provider.
@@ -3,6 +3,8 @@
 async function updateBadge(type) {
     const data = new FormData(event.currentTarget);
     const value = Object.fromEntries(data.entries());
+    // Show failures in the preview rather than a broken image
+    value.errorBadge = true;
     const queryString = $.param(value);
 
     const baseUrl = window.location.origin;
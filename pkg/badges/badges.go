@@ -1,14 +1,20 @@
 package badges
 
 import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/flosch/pongo2/v6"
-	"github.com/golang/freetype/truetype"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
 	"github.com/wrouesnel/badgeserv/assets"
+	"go.uber.org/zap"
 )
 
 // ColorMapping provides structure for returning and sorting color mappings.
@@ -20,41 +26,107 @@ type ColorMapping struct {
 // BadgeConfig provides configuration for a badge service.
 type BadgeConfig struct {
 	FontSize     float64           `help:"Font size of badges" default:"11"`
+	FontFile     string            `help:"TTF or OTF font to measure and render badge text with (default is the embedded DejaVu Sans)" default:""`
 	XSpacing     int               `help:"X Spacing of Badge Elements" default:"8"`
-	DefaultColor string            `help:"Default badge color" default:"4c1"`
+	DefaultColor string            `help:"Badge color used when a color is missing or invalid" default:"4c1"`
+	DefaultStyle string            `help:"Default badge style (flat, flat-square, plastic, for-the-badge or social)" default:"flat"`
+	IconDir      string            `help:"Directory of additional logo icons (<name>.svg or <name>.png)" default:""`
 	ColorList    map[string]string `help:"Plaintext badge colors" default:"brightgreen=4c1;green=97CA00;yellow=dfb317;yellowgreen=a4a61d;orange=fe7d37;red=e05d44;blue=007ec6;grey=555;gray=555;lightgrey=9f9f9f;lightgray=9f9f9f"`
 }
 
+// Segment is a section of a badge following the label.
+type Segment struct {
+	Text  string
+	Color string
+}
+
 // BadgeDesc is all the data to generate a badge.
 type BadgeDesc struct {
 	Title string
 	Text  string
 	Color string
+	// Segments follow the label instead of Text and Color if not empty.
+	Segments []Segment
+	// LabelColor is the color of the label. The default label color is used if empty.
+	LabelColor string
+	// Style selects the badge style. The configured default style is used if empty.
+	Style string
+	// Logo is an icon name or image data URI shown before the label.
+	Logo string
+	// LogoColor replaces "currentColor" in SVG logos. The style default is used if empty.
+	LogoColor string
+	// LogoWidth is the width of the logo. A default width is used if zero.
+	LogoWidth int
+	// Font is the path of a TTF or OTF font file for the badge text. The server font is used if empty.
+	Font string
+	// NoLabel leaves out the label segment if there is no title or logo, as shields.io does for
+	// message-color badges. Otherwise an empty label is still drawn.
+	NoLabel bool
 }
 
-// BadgeService implements generating badge SVGs.
+// BadgeService implements generating badge SVGs and PNGs.
 type BadgeService interface {
 	CreateBadge(desc BadgeDesc) (string, error)
+	CreateBadgePNG(desc BadgeDesc, scale float64) ([]byte, error)
+	ResolveColors(desc BadgeDesc) BadgeDesc
 	Colors() []ColorMapping
+	Styles() []Style
+	Logos() []string
 }
 
+// defaultLabelColor is the background color of badge labels.
+const defaultLabelColor = "555"
+
 // badgeService implements the actual badge generator.
 type badgeService struct {
-	config        *BadgeConfig
-	badgeTemplate *pongo2.Template
-	fontCalc      *FontCalculator
+	config         *BadgeConfig
+	defaultStyle   Style
+	defaultColor   color.NRGBA
+	styleTemplates map[Style]*pongo2.Template
+	defaultFont    *badgeFont
+	// fonts caches fonts loaded from files.
+	fonts    map[string]cachedFont
+	fontsMtx sync.Mutex
 }
 
 // NewBadgeService initializes a new BadgeService interface.
 func NewBadgeService(config *BadgeConfig) BadgeService {
-	font := lo.Must(truetype.Parse(lo.Must(assets.ReadFile("fonts/DejaVuSans.ttf"))))
-	fontCalc := NewFontCalculator(font)
+	defaultFont := lo.Must(parseFont(lo.Must(assets.ReadFile("fonts/DejaVuSans.ttf"))))
+
+	styleTemplates := lo.SliceToMap(Styles(), func(style Style) (Style, *pongo2.Template) {
+		return style, lo.Must(pongo2.FromBytes(lo.Must(assets.ReadFile(style.templateName()))))
+	})
+
+	defaultStyle, err := ParseStyle(config.DefaultStyle)
+	if err != nil {
+		zap.L().Warn("Invalid default badge style, using flat", zap.Error(err))
+		defaultStyle = StyleFlat
+	}
+
+	bs := &badgeService{
+		config:         config,
+		defaultStyle:   defaultStyle,
+		styleTemplates: styleTemplates,
+		defaultFont:    defaultFont,
+		fonts:          map[string]cachedFont{},
+	}
+
+	bs.defaultColor, err = bs.parseColor(config.DefaultColor)
+	if err != nil {
+		zap.L().Warn("Invalid default badge color, using "+fallbackColor, zap.Error(err))
+		bs.defaultColor = lo.Must(ParseColor(fallbackColor))
+	}
 
-	return &badgeService{
-		config:        config,
-		badgeTemplate: lo.Must(pongo2.FromBytes(lo.Must(assets.ReadFile("badges/badge.svg.p2")))),
-		fontCalc:      fontCalc,
+	if config.FontFile != "" {
+		font, err := bs.loadFont(config.FontFile)
+		if err != nil {
+			zap.L().Warn("Invalid badge font, using DejaVu Sans", zap.Error(err))
+		} else {
+			bs.defaultFont = font
+		}
 	}
+
+	return bs
 }
 
 // Colors returns the current configured color mappings.
@@ -73,30 +145,133 @@ func (bs *badgeService) Colors() []ColorMapping {
 	return colors
 }
 
-// CreateBadge takes the given parameters and generates an SVG for the badge
-//nolint:gomnd
-func (bs *badgeService) CreateBadge(desc BadgeDesc) (string, error) {
-	titleW, _ := bs.fontCalc.TextWidth(bs.config.FontSize, desc.Title)
-	textW, _ := bs.fontCalc.TextWidth(bs.config.FontSize, desc.Text)
-
-	width := titleW + textW + 4*bs.config.XSpacing
+// Styles returns the supported badge styles.
+func (bs *badgeService) Styles() []Style {
+	return Styles()
+}
 
-	if c, ok := bs.config.ColorList[desc.Color]; ok {
-		desc.Color = c
+// CreateBadge takes the given parameters and generates an SVG for the badge.
+func (bs *badgeService) CreateBadge(desc BadgeDesc) (string, error) {
+	style, layout, logo, err := bs.prepare(desc)
+	if err != nil {
+		return "", errors.Wrap(err, "CreateBadge")
+	}
+	if logo != nil {
+		layout.Logo.Href = logo.dataURI()
 	}
 
-	result, err := bs.badgeTemplate.Execute(map[string]interface{}{
-		"Width":       width,
-		"TitleWidth":  titleW + 2*bs.config.XSpacing,
-		"Title":       desc.Title,
-		"Text":        desc.Text,
-		"TitleAnchor": titleW/2 + bs.config.XSpacing,
-		"TextAnchor":  titleW + textW/2 + 3*bs.config.XSpacing,
-		"Color":       desc.Color,
+	result, err := bs.styleTemplates[style].Execute(pongo2.Context{
+		"Width":         layout.Width,
+		"Height":        layout.Height,
+		"LetterSpacing": layout.LetterSpacing,
+		"FontSize":      strconv.FormatFloat(layout.FontSize, 'f', -1, 64),
+		"FontFamily":    layout.FontFamily,
+		"TextY":         layout.TextY,
+		"ArrowY":        layout.ArrowY,
+		"Segments":      layout.Segments,
+		"Logo":          layout.Logo,
 	})
-
 	if err != nil {
 		return result, errors.Wrap(err, "CreateBadge: error templating")
 	}
 	return result, nil
 }
+
+// CreateBadgePNG takes the given parameters and generates a PNG of the badge. scale multiplies the
+// size of the badge. A scale of 0 renders the badge at its normal size.
+func (bs *badgeService) CreateBadgePNG(desc BadgeDesc, scale float64) ([]byte, error) {
+	if scale == 0 {
+		scale = 1
+	}
+	if scale < minScale || scale > maxScale {
+		return nil, errors.Wrapf(ErrInvalidScale, "CreateBadgePNG: scale must be between %v and %v", minScale, maxScale)
+	}
+
+	style, layout, logo, err := bs.prepare(desc)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateBadgePNG")
+	}
+
+	img, err := bs.rasterize(style, layout, logo, scale)
+	if err != nil {
+		return nil, errors.Wrap(err, "CreateBadgePNG")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, errors.Wrap(err, "CreateBadgePNG: error encoding")
+	}
+	return buf.Bytes(), nil
+}
+
+// ResolveColors returns the badge with its colors replaced by the CSS colors it is drawn with.
+func (bs *badgeService) ResolveColors(desc BadgeDesc) BadgeDesc {
+	desc.LabelColor = cssColor(bs.resolveColor(desc.LabelColor, lo.Must(ParseColor(defaultLabelColor))))
+	desc.Color = cssColor(bs.resolveColor(desc.Color, bs.defaultColor))
+	desc.Segments = lo.Map(desc.Segments, func(segment Segment, _ int) Segment {
+		return Segment{Text: segment.Text, Color: cssColor(bs.resolveColor(segment.Color, bs.defaultColor))}
+	})
+	return desc
+}
+
+// prepare resolves the style and logo of a badge and lays it out.
+func (bs *badgeService) prepare(desc BadgeDesc) (Style, badgeLayout, *logoImage, error) {
+	style := bs.defaultStyle
+	if desc.Style != "" {
+		var err error
+		style, err = ParseStyle(desc.Style)
+		if err != nil {
+			return "", badgeLayout{}, nil, err
+		}
+	}
+
+	if len(desc.Segments) == 0 {
+		desc.Segments = []Segment{{Text: desc.Text, Color: desc.Color}}
+	}
+
+	// Invalid colors fall back to the defaults rather than failing, since colors are often templated.
+	segments := make([]badgeSegment, 0, len(desc.Segments)+1)
+	if !desc.NoLabel || desc.Title != "" || desc.Logo != "" {
+		segments = append(segments, badgeSegment{
+			Text:  desc.Title,
+			Color: bs.resolveColor(desc.LabelColor, lo.Must(ParseColor(defaultLabelColor))),
+			Label: true,
+		})
+	}
+	for _, segment := range desc.Segments {
+		segments = append(segments, badgeSegment{Text: segment.Text, Color: bs.resolveColor(segment.Color, bs.defaultColor)})
+	}
+
+	var logo *logoImage
+	logoWidth := 0
+	if desc.Logo != "" {
+		logoWidth = lo.Ternary(desc.LogoWidth > 0, desc.LogoWidth,
+			int(math.Round(defaultLogoWidth*bs.config.FontSize/referenceFontSize)))
+		if logoWidth > maxLogoWidth {
+			return "", badgeLayout{}, nil, errors.Wrapf(ErrInvalidLogo, "logo width must not exceed %d", maxLogoWidth)
+		}
+
+		logoColor, err := bs.parseColor(lo.Ternary(desc.LogoColor != "", desc.LogoColor, styleSpecs[style].LogoColor))
+		if err != nil {
+			return "", badgeLayout{}, nil, errors.Wrapf(ErrInvalidLogo, "logo color: %s", err.Error())
+		}
+
+		logo, err = bs.resolveLogo(desc.Logo, cssColor(logoColor))
+		if err != nil {
+			return "", badgeLayout{}, nil, err
+		}
+	}
+
+	font := bs.defaultFont
+	if desc.Font != "" {
+		var err error
+		font, err = bs.loadFont(desc.Font)
+		if err != nil {
+			return "", badgeLayout{}, nil, err
+		}
+	}
+
+	layout := bs.layout(style, font, segments, logoWidth)
+
+	return style, layout, logo, nil
+}
@@ -0,0 +1,187 @@
+package badges
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"golang.org/x/image/colornames"
+)
+
+var (
+	ErrInvalidColor = errors.New("invalid color")
+)
+
+// fallbackColor is used if the configured default color is invalid.
+const fallbackColor = "4c1"
+
+// hexColorRegex matches hex colors with or without a leading '#'.
+var hexColorRegex = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`) //nolint:gochecknoglobals
+
+// colorFunctionRegex matches CSS color functions, capturing the function name and its arguments.
+var colorFunctionRegex = regexp.MustCompile(`^(rgba?|hsla?)\((.*)\)$`) //nolint:gochecknoglobals
+
+// ParseColor parses a CSS color. Hex colors of 3, 6 or 8 digits with or without a leading '#',
+// the rgb(), rgba(), hsl() and hsla() functions and CSS color names are accepted.
+func ParseColor(value string) (color.NRGBA, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if hexColorRegex.MatchString(value) {
+		return parseHex(strings.TrimPrefix(value, "#")), nil
+	}
+
+	if match := colorFunctionRegex.FindStringSubmatch(value); match != nil {
+		c, err := parseColorFunction(match[1], match[2])
+		if err != nil {
+			return color.NRGBA{}, errors.Wrapf(ErrInvalidColor, "%q: %s", value, err.Error())
+		}
+		return c, nil
+	}
+
+	if c, ok := colornames.Map[value]; ok {
+		return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}, nil
+	}
+
+	return color.NRGBA{}, errors.Wrapf(ErrInvalidColor, "%q", value)
+}
+
+// parseHex parses 3, 6 or 8 hex digits.
+func parseHex(value string) color.NRGBA {
+	if len(value) == 3 { //nolint:gomnd
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if len(value) == 6 { //nolint:gomnd
+		value += "ff"
+	}
+	rgba, _ := strconv.ParseUint(value, 16, 32)
+	return color.NRGBA{R: uint8(rgba >> 24), G: uint8(rgba >> 16), B: uint8(rgba >> 8), A: uint8(rgba)} //nolint:gomnd
+}
+
+// parseColorFunction parses the arguments of a CSS color function. Arguments may be separated by
+// commas or spaces, with the alpha optionally following a '/'.
+func parseColorFunction(name string, args string) (color.NRGBA, error) {
+	fields := strings.Fields(strings.NewReplacer(",", " ", "/", " ").Replace(args))
+	if len(fields) != 3 && len(fields) != 4 {
+		return color.NRGBA{}, errors.Errorf("%s() takes 3 or 4 arguments", name)
+	}
+
+	alpha := 1.0
+	if len(fields) == 4 { //nolint:gomnd
+		var err error
+		alpha, err = parseColorComponent(fields[3], 1)
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+	}
+
+	var r, g, b float64
+	if strings.HasPrefix(name, "rgb") {
+		for idx, component := range []*float64{&r, &g, &b} {
+			value, err := parseColorComponent(fields[idx], 255) //nolint:gomnd
+			if err != nil {
+				return color.NRGBA{}, err
+			}
+			*component = value / 255 //nolint:gomnd
+		}
+	} else {
+		hue, err := strconv.ParseFloat(strings.TrimSuffix(fields[0], "deg"), 64)
+		if err != nil {
+			return color.NRGBA{}, errors.Errorf("invalid hue %q", fields[0])
+		}
+		saturation, err := parsePercentage(fields[1])
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		lightness, err := parsePercentage(fields[2])
+		if err != nil {
+			return color.NRGBA{}, err
+		}
+		r, g, b = hslToRGB(hue, saturation, lightness)
+	}
+
+	toByte := func(v float64) uint8 {
+		return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255)) //nolint:gomnd
+	}
+	return color.NRGBA{R: toByte(r), G: toByte(g), B: toByte(b), A: toByte(alpha)}, nil
+}
+
+// parseColorComponent parses a number or a percentage of max, clamped between 0 and max.
+func parseColorComponent(value string, max float64) (float64, error) {
+	if strings.HasSuffix(value, "%") {
+		percentage, err := parsePercentage(value)
+		return percentage * max, err
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid color component %q", value)
+	}
+	return math.Max(0, math.Min(max, number)), nil
+}
+
+// parsePercentage parses a percentage to a fraction between 0 and 1.
+func parsePercentage(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil || !strings.HasSuffix(value, "%") {
+		return 0, errors.Errorf("invalid percentage %q", value)
+	}
+	return math.Max(0, math.Min(1, number/100)), nil //nolint:gomnd
+}
+
+// hslToRGB converts a hue in degrees, and a saturation and lightness between 0 and 1 to RGB.
+func hslToRGB(hue, saturation, lightness float64) (float64, float64, float64) {
+	hue = math.Mod(math.Mod(hue, 360)+360, 360) / 360 //nolint:gomnd
+	if saturation == 0 {
+		return lightness, lightness, lightness
+	}
+
+	q := lo.Ternary(lightness < 0.5, lightness*(1+saturation), lightness+saturation-lightness*saturation) //nolint:gomnd
+	p := 2*lightness - q                                                                                  //nolint:gomnd
+	channel := func(t float64) float64 {
+		t = math.Mod(t+1, 1)
+		switch {
+		case t < 1.0/6: //nolint:gomnd
+			return p + (q-p)*6*t //nolint:gomnd
+		case t < 1.0/2: //nolint:gomnd
+			return q
+		case t < 2.0/3: //nolint:gomnd
+			return p + (q-p)*(2.0/3-t)*6 //nolint:gomnd
+		default:
+			return p
+		}
+	}
+	return channel(hue + 1.0/3), channel(hue), channel(hue - 1.0/3) //nolint:gomnd
+}
+
+// cssColor formats a color for SVG attributes.
+func cssColor(c color.NRGBA) string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("rgba(%d,%d,%d,%s)", c.R, c.G, c.B,
+		strconv.FormatFloat(math.Round(float64(c.A)/255*1000)/1000, 'f', -1, 64)) //nolint:gomnd
+}
+
+// parseColor parses a color, looking up names in the configured color list before CSS color names.
+func (bs *badgeService) parseColor(value string) (color.NRGBA, error) {
+	if c, ok := bs.config.ColorList[strings.TrimSpace(value)]; ok {
+		value = c
+	}
+	return ParseColor(value)
+}
+
+// resolveColor parses a color, falling back to the given color if value is empty or invalid.
+func (bs *badgeService) resolveColor(value string, fallback color.NRGBA) color.NRGBA {
+	if value == "" {
+		return fallback
+	}
+	c, err := bs.parseColor(value)
+	if err != nil {
+		return fallback
+	}
+	return c
+}
@@ -0,0 +1,86 @@
+package badges
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseColor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value string
+		want  color.NRGBA
+		err   error
+	}{
+		{"4c1", color.NRGBA{R: 0x44, G: 0xcc, B: 0x11, A: 0xff}, nil},
+		{"#4C1", color.NRGBA{R: 0x44, G: 0xcc, B: 0x11, A: 0xff}, nil},
+		{"e05d44", color.NRGBA{R: 0xe0, G: 0x5d, B: 0x44, A: 0xff}, nil},
+		{" #E05D44 ", color.NRGBA{R: 0xe0, G: 0x5d, B: 0x44, A: 0xff}, nil},
+		{"#e05d4480", color.NRGBA{R: 0xe0, G: 0x5d, B: 0x44, A: 0x80}, nil},
+		{"rgb(255, 128, 0)", color.NRGBA{R: 255, G: 128, B: 0, A: 0xff}, nil},
+		{"rgb(100% 50% 0%)", color.NRGBA{R: 255, G: 128, B: 0, A: 0xff}, nil},
+		{"rgb(300, -5, 0)", color.NRGBA{R: 255, G: 0, B: 0, A: 0xff}, nil},
+		{"rgba(255, 128, 0, 0.5)", color.NRGBA{R: 255, G: 128, B: 0, A: 128}, nil},
+		{"rgba(255 128 0 / 50%)", color.NRGBA{R: 255, G: 128, B: 0, A: 128}, nil},
+		{"hsl(120, 100%, 50%)", color.NRGBA{R: 0, G: 255, B: 0, A: 0xff}, nil},
+		{"hsl(-120deg 100% 50%)", color.NRGBA{R: 0, G: 0, B: 255, A: 0xff}, nil},
+		{"hsl(0, 0%, 50%)", color.NRGBA{R: 128, G: 128, B: 128, A: 0xff}, nil},
+		{"hsla(0, 100%, 50%, 0.25)", color.NRGBA{R: 255, G: 0, B: 0, A: 64}, nil},
+		{"darkorange", color.NRGBA{R: 0xff, G: 0x8c, B: 0, A: 0xff}, nil},
+		{"Red", color.NRGBA{R: 0xff, G: 0, B: 0, A: 0xff}, nil},
+		{"", color.NRGBA{}, ErrInvalidColor},
+		{"#12", color.NRGBA{}, ErrInvalidColor},
+		{"#12345", color.NRGBA{}, ErrInvalidColor},
+		{"ggg", color.NRGBA{}, ErrInvalidColor},
+		{"rgb(1, 2)", color.NRGBA{}, ErrInvalidColor},
+		{"rgb(a, b, c)", color.NRGBA{}, ErrInvalidColor},
+		{"hsl(0, 100, 50%)", color.NRGBA{}, ErrInvalidColor},
+		{"notacolor", color.NRGBA{}, ErrInvalidColor},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseColor(test.value)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("ParseColor error = %v, want %v", err, test.err)
+			}
+			if got != test.want {
+				t.Errorf("ParseColor = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	t.Parallel()
+
+	fallback := color.NRGBA{R: 1, G: 2, B: 3, A: 0xff}
+	bs := &badgeService{config: &BadgeConfig{ColorList: map[string]string{"red": "e05d44"}}}
+
+	tests := []struct {
+		name  string
+		value string
+		want  color.NRGBA
+	}{
+		{"empty", "", fallback},
+		{"invalid", "notacolor", fallback},
+		{"invalid function", "rgb(1, 2)", fallback},
+		{"color list", "red", color.NRGBA{R: 0xe0, G: 0x5d, B: 0x44, A: 0xff}},
+		{"css color", "blue", color.NRGBA{R: 0, G: 0, B: 0xff, A: 0xff}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			if got := bs.resolveColor(test.value, fallback); got != test.want {
+				t.Errorf("resolveColor(%q) = %v, want %v", test.value, got, test.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,26 @@
 package badges
 
 import (
-	"github.com/golang/freetype/truetype"
+	"math"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
 	"golang.org/x/image/math/fixed"
 )
 
+var (
+	ErrInvalidFont = errors.New("invalid font")
+)
+
 type FontCalculator struct {
-	font *truetype.Font
+	font *sfnt.Font
 }
 
-func NewFontCalculator(font *truetype.Font) *FontCalculator {
+func NewFontCalculator(font *sfnt.Font) *FontCalculator {
 	if font == nil {
 		zap.L().Warn("Initialized FontCalculator with nil font. Returning nil!")
 		return nil
@@ -20,19 +30,106 @@ func NewFontCalculator(font *truetype.Font) *FontCalculator {
 }
 
 func (fc *FontCalculator) TextWidth(fontSize float64, text string) (int, error) {
-	scale := fontSize / float64(fc.font.FUnitsPerEm())
+	scale := fontSize / float64(fc.font.UnitsPerEm())
+	// A ppem of UnitsPerEm in 26.6 fixed point measures in font units, so nothing is rounded until the end.
+	ppem := fixed.Int26_6(fc.font.UnitsPerEm())
+	buf := new(sfnt.Buffer)
 
 	width := 0
-	prev, hasPrev := truetype.Index(0), false
+	prev, hasPrev := sfnt.GlyphIndex(0), false
 	for _, r := range text {
-		fUnitsPerEm := fixed.Int26_6(fc.font.FUnitsPerEm())
-		index := fc.font.Index(r)
+		index, err := fc.font.GlyphIndex(buf, r)
+		if err != nil {
+			return 0, errors.Wrap(err, "TextWidth")
+		}
 		if hasPrev {
-			width += int(fc.font.Kern(fUnitsPerEm, prev, index))
+			kern, err := fc.font.Kern(buf, prev, index, ppem, font.HintingNone)
+			if err == nil {
+				width += int(kern)
+			}
 		}
-		width += int(fc.font.HMetric(fUnitsPerEm, index).AdvanceWidth)
+		advance, err := fc.font.GlyphAdvance(buf, index, ppem, font.HintingNone)
+		if err != nil {
+			return 0, errors.Wrap(err, "TextWidth")
+		}
+		width += int(advance)
 		prev, hasPrev = index, true
 	}
 
 	return int(float64(width) * scale), nil
 }
+
+// CapHeight returns the height of capital letters at the given font size.
+func (fc *FontCalculator) CapHeight(fontSize float64) float64 {
+	metrics, err := fc.font.Metrics(new(sfnt.Buffer), fixed.Int26_6(fc.font.UnitsPerEm()), font.HintingNone)
+	if err != nil {
+		return 0
+	}
+	// Font metrics are measured downwards, so the cap height is negative.
+	return math.Abs(float64(metrics.CapHeight)) * fontSize / float64(fc.font.UnitsPerEm())
+}
+
+// badgeFont is a font used to measure and render badge text.
+type badgeFont struct {
+	font *sfnt.Font
+	// family is the font family name given to SVG renderers, so they draw the measured font.
+	family string
+	calc   *FontCalculator
+}
+
+// parseFont parses a TrueType or OpenType font.
+func parseFont(data []byte) (*badgeFont, error) {
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFont, err.Error())
+	}
+
+	buf := new(sfnt.Buffer)
+	family, err := parsed.Name(buf, sfnt.NameIDTypographicFamily)
+	if err != nil || family == "" {
+		family, err = parsed.Name(buf, sfnt.NameIDFamily)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidFont, "font has no family name: %s", err.Error())
+		}
+	}
+
+	return &badgeFont{
+		font:   parsed,
+		family: family,
+		calc:   NewFontCalculator(parsed),
+	}, nil
+}
+
+// cachedFont is a font loaded from a file, and the file's modification time and size when it was loaded.
+type cachedFont struct {
+	font    *badgeFont
+	modTime time.Time
+	size    int64
+}
+
+// loadFont returns the font in a file. Fonts are cached after they are first loaded, and loaded again if
+// the file's modification time or size changes, so replaced font files are used after a config reload.
+func (bs *badgeService) loadFont(path string) (*badgeFont, error) {
+	bs.fontsMtx.Lock()
+	defer bs.fontsMtx.Unlock()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFont, err.Error())
+	}
+	if cached, ok := bs.fonts[path]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.font, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFont, err.Error())
+	}
+	loaded, err := parseFont(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", path)
+	}
+
+	bs.fonts[path] = cachedFont{font: loaded, modTime: info.ModTime(), size: info.Size()}
+	return loaded, nil
+}
@@ -0,0 +1,56 @@
+package badges
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestLoadFont(t *testing.T) {
+	t.Parallel()
+
+	bs := &badgeService{fonts: map[string]cachedFont{}}
+	path := filepath.Join(t.TempDir(), "font.ttf")
+	modTime := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		data   []byte
+		touch  bool
+		family string
+		err    error
+	}{
+		{"loaded", goregular.TTF, true, "Go", nil},
+		{"unchanged file is cached", make([]byte, len(goregular.TTF)), false, "Go", nil},
+		{"replaced", gomono.TTF, true, "Go Mono", nil},
+		{"replaced with an invalid font", []byte("not a font"), true, "", ErrInvalidFont},
+		{"fixed", goregular.TTF, true, "Go", nil},
+	}
+
+	// The steps share the font file, so they run in order.
+	for _, test := range tests {
+		if err := os.WriteFile(path, test.data, 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if test.touch {
+			modTime = modTime.Add(time.Second)
+		}
+		// Without touch the file keeps its modification time, so it looks unchanged if its size is the same.
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+
+		font, err := bs.loadFont(path)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%s: loadFont error = %v, want %v", test.name, err, test.err)
+		}
+		if err == nil && font.family != test.family {
+			t.Errorf("%s: family = %q, want %q", test.name, font.family, test.family)
+		}
+	}
+}
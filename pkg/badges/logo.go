@@ -0,0 +1,162 @@
+package badges
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/assets"
+)
+
+var (
+	ErrUnknownLogo = errors.New("unknown logo")
+	ErrInvalidLogo = errors.New("invalid logo")
+)
+
+const (
+	// defaultLogoWidth is the width of logos when no width is requested.
+	defaultLogoWidth = 14
+	// maxLogoWidth bounds requested logo widths.
+	maxLogoWidth = 100
+	// logoHeight is the height logos are scaled to.
+	logoHeight = 14
+	// logoSpacing separates the logo from the text of its segment.
+	logoSpacing = 3
+	// iconsDir is the asset directory of the embedded icons.
+	iconsDir = "icons"
+	// logoCurrentColor is replaced in SVG logos with the logo color.
+	logoCurrentColor = "currentColor"
+)
+
+// logoMediaTypes lists the image types accepted for logos.
+var logoMediaTypes = []string{"image/svg+xml", "image/png", "image/jpeg", "image/gif"} //nolint:gochecknoglobals
+
+// logoNameRegex matches names which can be looked up in the icon directories.
+var logoNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`) //nolint:gochecknoglobals
+
+// layoutLogo is a logo positioned for rendering. Href is a data URI, set when rendering SVG badges.
+type layoutLogo struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+	Href   string
+}
+
+// Logos returns the names of the embedded icons and the icons in the configured icon directory.
+func (bs *badgeService) Logos() []string {
+	names := []string{}
+	if entries, err := fs.ReadDir(assets.Assets(), iconsDir); err == nil {
+		names = append(names, lo.FilterMap(entries, func(entry fs.DirEntry, _ int) (string, bool) {
+			return strings.TrimSuffix(entry.Name(), ".svg"), path.Ext(entry.Name()) == ".svg"
+		})...)
+	}
+
+	if bs.config.IconDir != "" {
+		// The icon directory may be missing or unreadable, in which case only the embedded icons are listed.
+		if entries, err := os.ReadDir(bs.config.IconDir); err == nil {
+			names = append(names, lo.FilterMap(entries, func(entry fs.DirEntry, _ int) (string, bool) {
+				ext := filepath.Ext(entry.Name())
+				name := strings.TrimSuffix(entry.Name(), ext)
+				return name, !entry.IsDir() && (ext == ".svg" || ext == ".png") && logoNameRegex.MatchString(name)
+			})...)
+		}
+	}
+
+	names = lo.Uniq(names)
+	sort.Strings(names)
+	return names
+}
+
+// logoImage is a resolved logo.
+type logoImage struct {
+	MediaType string
+	Data      []byte
+}
+
+// dataURI returns the logo as a base64 data URI.
+func (l *logoImage) dataURI() string {
+	return fmt.Sprintf("data:%s;base64,%s", l.MediaType, base64.StdEncoding.EncodeToString(l.Data))
+}
+
+// resolveLogo returns the image for a logo. logo may be a data URI, the name of an icon in the
+// configured icon directory, or the name of an embedded icon. SVG logos have "currentColor"
+// replaced with color.
+func (bs *badgeService) resolveLogo(logo string, color string) (*logoImage, error) {
+	mediaType, data, err := bs.readLogo(logo)
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaType == "image/svg+xml" {
+		data = bytes.ReplaceAll(data, []byte(logoCurrentColor), []byte(color))
+	}
+
+	return &logoImage{MediaType: mediaType, Data: data}, nil
+}
+
+// readLogo returns the media type and content of a logo.
+func (bs *badgeService) readLogo(logo string) (string, []byte, error) {
+	if strings.HasPrefix(logo, "data:") {
+		return parseLogoDataURI(logo)
+	}
+
+	if !logoNameRegex.MatchString(logo) {
+		return "", nil, errors.Wrapf(ErrUnknownLogo, "%q", logo)
+	}
+
+	// Icons in the icon directory take precedence over embedded icons.
+	if bs.config.IconDir != "" {
+		for _, ext := range []string{".svg", ".png"} {
+			data, err := os.ReadFile(filepath.Join(bs.config.IconDir, logo+ext))
+			if err == nil {
+				return lo.Ternary(ext == ".svg", "image/svg+xml", "image/png"), data, nil
+			}
+		}
+	}
+
+	data, err := assets.ReadFile(path.Join(iconsDir, logo+".svg"))
+	if err != nil {
+		return "", nil, errors.Wrapf(ErrUnknownLogo, "%q", logo)
+	}
+	return "image/svg+xml", data, nil
+}
+
+// parseLogoDataURI decodes a base64 or percent-encoded image data URI.
+func parseLogoDataURI(dataURI string) (string, []byte, error) {
+	header, payload, found := strings.Cut(strings.TrimPrefix(dataURI, "data:"), ",")
+	if !found {
+		return "", nil, errors.Wrap(ErrInvalidLogo, "malformed data URI")
+	}
+
+	params := strings.Split(header, ";")
+	mediaType := strings.ToLower(params[0])
+	if !lo.Contains(logoMediaTypes, mediaType) {
+		return "", nil, errors.Wrapf(ErrInvalidLogo, "unsupported logo type %q", mediaType)
+	}
+
+	if lo.Contains(params[1:], "base64") {
+		// Query string decoding turns '+' into spaces, so put them back.
+		data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(payload, " ", "+"))
+		if err != nil {
+			return "", nil, errors.Wrapf(ErrInvalidLogo, "invalid base64 data: %s", err.Error())
+		}
+		return mediaType, data, nil
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return "", nil, errors.Wrapf(ErrInvalidLogo, "invalid data: %s", err.Error())
+	}
+	return mediaType, []byte(data), nil
+}
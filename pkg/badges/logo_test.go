@@ -0,0 +1,55 @@
+package badges
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestLogos(t *testing.T) {
+	t.Parallel()
+
+	iconDir := t.TempDir()
+	for _, name := range []string{"custom.svg", "raster.png", "book.svg", "notes.txt", ".hidden.svg"} {
+		if err := os.WriteFile(filepath.Join(iconDir, name), []byte("<svg/>"), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(iconDir, "dir.svg"), 0o700); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		iconDir  string
+		included []string
+		excluded []string
+	}{
+		{"embedded", "", []string{"book", "bug", "warning"}, []string{"custom", "raster"}},
+		{"icon dir", iconDir, []string{"book", "custom", "raster"}, []string{"notes", ".hidden", "dir"}},
+		{"missing icon dir", filepath.Join(iconDir, "missing"), []string{"book"}, []string{"custom"}},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			logos := (&badgeService{config: &BadgeConfig{IconDir: test.iconDir}}).Logos()
+			for _, name := range test.included {
+				if !lo.Contains(logos, name) {
+					t.Errorf("Logos() = %v, missing %q", logos, name)
+				}
+			}
+			for _, name := range test.excluded {
+				if lo.Contains(logos, name) {
+					t.Errorf("Logos() = %v, includes %q", logos, name)
+				}
+			}
+			if len(lo.Uniq(logos)) != len(logos) {
+				t.Errorf("Logos() = %v, has duplicates", logos)
+			}
+		})
+	}
+}
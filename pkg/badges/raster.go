@@ -0,0 +1,347 @@
+package badges
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // Decodes GIF logos.
+	_ "image/jpeg" // Decodes JPEG logos.
+	"math"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+var (
+	ErrInvalidScale = errors.New("invalid badge scale")
+)
+
+const (
+	// minScale and maxScale bound the scale of PNG badges.
+	minScale = 0.5
+	maxScale = 8
+	// boldOffset is how far text is drawn twice to embolden it, since only a regular font is available.
+	boldOffset = 0.5
+	// maxLogoPixels bounds the size of raster logos, which are decoded in full before being scaled down. A
+	// small compressed image can otherwise decode to enough pixels to exhaust memory.
+	maxLogoPixels = 2048 * 2048
+)
+
+// gradientStop is a color stop of a vertical gradient. Offset is from 0 at the top to 1 at the bottom.
+type gradientStop struct {
+	Offset float64
+	Color  color.NRGBA
+}
+
+// rasterSpec describes how a style is drawn as a PNG. It mirrors the badges/<style>.svg.p2 template.
+type rasterSpec struct {
+	// Radius of the badge corners.
+	Radius float64
+	// Gradient is drawn over the segments.
+	Gradient []gradientStop
+	// TextColor is the color of the segment text.
+	TextColor color.NRGBA
+	// ShadowColor is the color of the text shadow, drawn a pixel below the text. No shadow is drawn if
+	// ShadowColor is transparent.
+	ShadowColor color.NRGBA
+	// Outlined draws each segment as an outlined box, joining segments with an arrow.
+	Outlined bool
+}
+
+//nolint:gochecknoglobals,gomnd
+var rasterSpecs = map[Style]rasterSpec{
+	StyleFlat: {
+		Radius: 3,
+		Gradient: []gradientStop{
+			{0, color.NRGBA{0xbb, 0xbb, 0xbb, 0x1a}},
+			{1, color.NRGBA{0, 0, 0, 0x1a}},
+		},
+		TextColor:   color.NRGBA{0xff, 0xff, 0xff, 0xff},
+		ShadowColor: color.NRGBA{0x01, 0x01, 0x01, 0x4d},
+	},
+	StyleFlatSquare: {
+		TextColor: color.NRGBA{0xff, 0xff, 0xff, 0xff},
+	},
+	StylePlastic: {
+		Radius: 4,
+		Gradient: []gradientStop{
+			{0, color.NRGBA{0xff, 0xff, 0xff, 0xb3}},
+			{0.1, color.NRGBA{0xaa, 0xaa, 0xaa, 0x1a}},
+			{0.9, color.NRGBA{0, 0, 0, 0x4d}},
+			{1, color.NRGBA{0, 0, 0, 0x80}},
+		},
+		TextColor:   color.NRGBA{0xff, 0xff, 0xff, 0xff},
+		ShadowColor: color.NRGBA{0x01, 0x01, 0x01, 0x4d},
+	},
+	StyleForTheBadge: {
+		TextColor: color.NRGBA{0xff, 0xff, 0xff, 0xff},
+	},
+	StyleSocial: {
+		Radius: 2,
+		Gradient: []gradientStop{
+			{0, color.NRGBA{0xfc, 0xfc, 0xfc, 0}},
+			{1, color.NRGBA{0, 0, 0, 0x1a}},
+		},
+		TextColor:   color.NRGBA{0x33, 0x33, 0x33, 0xff},
+		ShadowColor: color.NRGBA{0xff, 0xff, 0xff, 0xff},
+		Outlined:    true,
+	},
+}
+
+//nolint:gochecknoglobals,gomnd
+var (
+	socialLabelColor   = color.NRGBA{0xfc, 0xfc, 0xfc, 0xff}
+	socialMessageColor = color.NRGBA{0xfa, 0xfa, 0xfa, 0xff}
+	socialOutlineColor = color.NRGBA{0xd5, 0xd5, 0xd5, 0xff}
+)
+
+// verticalGradient is an unbounded image which changes color from top to bottom.
+type verticalGradient struct {
+	stops  []gradientStop
+	height float64
+}
+
+func (g *verticalGradient) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+func (g *verticalGradient) Bounds() image.Rectangle {
+	return image.Rect(math.MinInt32, math.MinInt32, math.MaxInt32, math.MaxInt32)
+}
+
+func (g *verticalGradient) At(_, y int) color.Color {
+	offset := (float64(y) + 0.5) / g.height
+	if offset <= g.stops[0].Offset {
+		return g.stops[0].Color
+	}
+	for idx := 1; idx < len(g.stops); idx++ {
+		prev, next := g.stops[idx-1], g.stops[idx]
+		if offset <= next.Offset {
+			t := (offset - prev.Offset) / (next.Offset - prev.Offset)
+			lerp := func(a, b uint8) uint8 {
+				return uint8(math.Round(float64(a) + t*(float64(b)-float64(a))))
+			}
+			return color.NRGBA{
+				R: lerp(prev.Color.R, next.Color.R),
+				G: lerp(prev.Color.G, next.Color.G),
+				B: lerp(prev.Color.B, next.Color.B),
+				A: lerp(prev.Color.A, next.Color.A),
+			}
+		}
+	}
+	return g.stops[len(g.stops)-1].Color
+}
+
+// roundedRect adds a rectangle with rounded corners to the rasterizer.
+func roundedRect(z *vector.Rasterizer, x0, y0, x1, y1, radius float64) {
+	r := float32(math.Min(radius, math.Min(x1-x0, y1-y0)/2)) //nolint:gomnd
+	left, top, right, bottom := float32(x0), float32(y0), float32(x1), float32(y1)
+	z.MoveTo(left+r, top)
+	z.LineTo(right-r, top)
+	z.QuadTo(right, top, right, top+r)
+	z.LineTo(right, bottom-r)
+	z.QuadTo(right, bottom, right-r, bottom)
+	z.LineTo(left+r, bottom)
+	z.QuadTo(left, bottom, left, bottom-r)
+	z.LineTo(left, top+r)
+	z.QuadTo(left, top, left+r, top)
+	z.ClosePath()
+}
+
+// fill draws src into dst through the shape added to a rasterizer by path.
+func fill(dst draw.Image, src image.Image, path func(z *vector.Rasterizer)) {
+	bounds := dst.Bounds()
+	z := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+	path(z)
+	z.Draw(dst, bounds, src, image.Point{})
+}
+
+// rasterize draws a laid out badge at the given scale.
+func (bs *badgeService) rasterize(style Style, layout badgeLayout, logo *logoImage, scale float64) (*image.RGBA, error) {
+	spec := rasterSpecs[style]
+	width, height := float64(layout.Width)*scale, float64(layout.Height)*scale
+
+	// Outlined badges are one pixel larger to fit the outline.
+	outline := lo.Ternary(spec.Outlined, scale, 0)
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(width+outline)), int(math.Ceil(height+outline))))
+
+	if spec.Outlined {
+		bs.rasterizeOutlined(img, spec, layout, scale)
+	} else {
+		bs.rasterizeFilled(img, spec, layout, scale)
+	}
+
+	if layout.Logo != nil && logo != nil {
+		if err := drawLogo(img, logo, image.Rect(
+			int(math.Round(float64(layout.Logo.X)*scale)),
+			int(math.Round(float64(layout.Logo.Y)*scale)),
+			int(math.Round(float64(layout.Logo.X+layout.Logo.Width)*scale)),
+			int(math.Round(float64(layout.Logo.Y+layout.Logo.Height)*scale)),
+		)); err != nil {
+			return nil, err
+		}
+	}
+
+	face, err := opentype.NewFace(layout.font.font, &opentype.FaceOptions{
+		Size:    layout.FontSize * scale,
+		DPI:     72, //nolint:gomnd
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "rasterize")
+	}
+	defer face.Close()
+
+	textY := float64(layout.TextY) * scale
+	for _, segment := range layout.Segments {
+		centerX := float64(segment.TextX) * scale
+		if spec.ShadowColor.A > 0 {
+			drawText(img, face, segment, centerX, textY+scale, layout.LetterSpacing*scale, spec.ShadowColor, scale)
+		}
+		drawText(img, face, segment, centerX, textY, layout.LetterSpacing*scale, spec.TextColor, scale)
+	}
+
+	return img, nil
+}
+
+// rasterizeFilled draws the segments side by side, clipped to the rounded outline of the badge.
+func (bs *badgeService) rasterizeFilled(img *image.RGBA, spec rasterSpec, layout badgeLayout, scale float64) {
+	body := image.NewRGBA(img.Bounds())
+	for _, segment := range layout.Segments {
+		draw.Draw(body, image.Rect(
+			int(math.Round(float64(segment.X)*scale)), 0,
+			int(math.Round(float64(segment.X+segment.Width)*scale)), body.Bounds().Dy(),
+		), image.NewUniform(segment.fill), image.Point{}, draw.Src)
+	}
+	if len(spec.Gradient) > 0 {
+		draw.Draw(body, body.Bounds(), &verticalGradient{stops: spec.Gradient, height: float64(body.Bounds().Dy())},
+			image.Point{}, draw.Over)
+	}
+
+	width, height := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	fill(img, body, func(z *vector.Rasterizer) {
+		roundedRect(z, 0, 0, width, height, spec.Radius*scale)
+	})
+}
+
+// rasterizeOutlined draws each segment as a separate outlined box, as the social style.
+func (bs *badgeService) rasterizeOutlined(img *image.RGBA, spec rasterSpec, layout badgeLayout, scale float64) {
+	outline := image.NewUniform(socialOutlineColor)
+	height := float64(layout.Height) * scale
+
+	for idx, segment := range layout.Segments {
+		x0, x1 := float64(segment.X)*scale, float64(segment.X+segment.Width)*scale
+		fill(img, outline, func(z *vector.Rasterizer) {
+			roundedRect(z, x0, 0, x1+scale, height+scale, spec.Radius*scale+scale/2)
+		})
+
+		if idx == 0 {
+			fill(img, image.NewUniform(socialLabelColor), func(z *vector.Rasterizer) {
+				roundedRect(z, x0+scale, scale, x1, height, spec.Radius*scale-scale/2)
+			})
+			fill(img, &verticalGradient{stops: spec.Gradient, height: height}, func(z *vector.Rasterizer) {
+				roundedRect(z, x0+scale, scale, x1, height, spec.Radius*scale-scale/2)
+			})
+			continue
+		}
+
+		fill(img, image.NewUniform(socialMessageColor), func(z *vector.Rasterizer) {
+			roundedRect(z, x0+scale, scale, x1, height, spec.Radius*scale-scale/2)
+		})
+		// The arrow pointing at the label overlaps the left edge of the segment.
+		arrowY := float64(layout.ArrowY) * scale
+		fill(img, outline, func(z *vector.Rasterizer) {
+			z.MoveTo(float32(x0+scale), float32(arrowY-scale/2))
+			z.LineTo(float32(x0-4.5*scale), float32(arrowY+3.5*scale))
+			z.LineTo(float32(x0+scale), float32(arrowY+7.5*scale))
+			z.ClosePath()
+		})
+		fill(img, image.NewUniform(socialMessageColor), func(z *vector.Rasterizer) {
+			z.MoveTo(float32(x0+1.5*scale), float32(arrowY+scale/2))
+			z.LineTo(float32(x0-3*scale), float32(arrowY+3.5*scale))
+			z.LineTo(float32(x0+1.5*scale), float32(arrowY+6.5*scale))
+			z.ClosePath()
+		})
+	}
+}
+
+// drawText draws the text of a segment centered on centerX.
+func drawText(img draw.Image, face font.Face, segment layoutSegment, centerX float64, baseline float64,
+	letterSpacing float64, textColor color.Color, scale float64) {
+	toFixed := func(v float64) fixed.Int26_6 {
+		return fixed.Int26_6(math.Round(v * 64)) //nolint:gomnd
+	}
+
+	width := font.MeasureString(face, segment.Text) + toFixed(letterSpacing)*fixed.Int26_6(len([]rune(segment.Text)))
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(textColor), Face: face}
+
+	passes := lo.Ternary(segment.Bold, 2, 1)
+	for pass := 0; pass < passes; pass++ {
+		drawer.Dot = fixed.Point26_6{
+			X: toFixed(centerX+float64(pass)*boldOffset*scale) - width/2,
+			Y: toFixed(baseline),
+		}
+		prev := rune(-1)
+		for _, r := range segment.Text {
+			if prev >= 0 {
+				drawer.Dot.X += face.Kern(prev, r)
+			}
+			drawer.DrawString(string(r))
+			drawer.Dot.X += toFixed(letterSpacing)
+			prev = r
+		}
+	}
+}
+
+// fitRect returns the largest rectangle with the aspect ratio of width and height centered in target.
+func fitRect(target image.Rectangle, width float64, height float64) (float64, float64, float64, float64) {
+	targetWidth, targetHeight := float64(target.Dx()), float64(target.Dy())
+	ratio := math.Min(targetWidth/width, targetHeight/height)
+	w, h := width*ratio, height*ratio
+	return float64(target.Min.X) + (targetWidth-w)/2, float64(target.Min.Y) + (targetHeight-h)/2, w, h //nolint:gomnd
+}
+
+// drawLogo draws a logo scaled to fit target.
+func drawLogo(img *image.RGBA, logo *logoImage, target image.Rectangle) error {
+	if logo.MediaType == "image/svg+xml" {
+		icon, err := oksvg.ReadIconStream(bytes.NewReader(logo.Data), oksvg.IgnoreErrorMode)
+		if err != nil {
+			return errors.Wrapf(ErrInvalidLogo, "SVG logo could not be parsed: %s", err.Error())
+		}
+		if icon.ViewBox.W <= 0 || icon.ViewBox.H <= 0 {
+			return errors.Wrap(ErrInvalidLogo, "SVG logo has no size")
+		}
+		icon.SetTarget(fitRect(target, icon.ViewBox.W, icon.ViewBox.H))
+
+		bounds := img.Bounds()
+		scanner := rasterx.NewScannerGV(bounds.Dx(), bounds.Dy(), img, bounds)
+		icon.Draw(rasterx.NewDasher(bounds.Dx(), bounds.Dy(), scanner), 1)
+		return nil
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(logo.Data))
+	if err != nil {
+		return errors.Wrapf(ErrInvalidLogo, "logo could not be decoded: %s", err.Error())
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxLogoPixels {
+		return errors.Wrapf(ErrInvalidLogo, "logo is %dx%d pixels, larger than the %d pixel limit",
+			config.Width, config.Height, maxLogoPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(logo.Data))
+	if err != nil {
+		return errors.Wrapf(ErrInvalidLogo, "logo could not be decoded: %s", err.Error())
+	}
+	x, y, w, h := fitRect(target, float64(src.Bounds().Dx()), float64(src.Bounds().Dy()))
+	xdraw.CatmullRom.Scale(img, image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h))),
+		src, src.Bounds(), draw.Over, nil)
+	return nil
+}
@@ -0,0 +1,196 @@
+package badges
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrUnknownStyle = errors.New("unknown badge style")
+)
+
+// Style selects the visual style of a badge. The styles match those offered by shields.io.
+type Style string
+
+const (
+	StyleFlat        Style = "flat"
+	StyleFlatSquare  Style = "flat-square"
+	StylePlastic     Style = "plastic"
+	StyleForTheBadge Style = "for-the-badge"
+	StyleSocial      Style = "social"
+)
+
+const (
+	// boldWidthFactor approximates how much wider bold text is than the regular font it is measured with.
+	boldWidthFactor = 1.1
+	// referenceFontSize is the font size the style heights are designed for. Heights are scaled
+	// with the configured font size.
+	referenceFontSize = 11
+	// arrowTop is how far above the middle of the badge the arrow joining segments in the social style starts.
+	arrowTop = 4
+)
+
+// styleSpec describes how a style lays out the segments of a badge. Each style is rendered by the
+// badges/<style>.svg.p2 template.
+type styleSpec struct {
+	// Height of the badge at the reference font size.
+	Height int
+	// FontScale scales the configured font size for the style.
+	FontScale float64
+	// Padding either side of the segment text, as a multiple of the configured XSpacing.
+	Padding float64
+	// Gap between segments.
+	Gap int
+	// LetterSpacing is added after every character.
+	LetterSpacing float64
+	// Uppercase renders all segment text in upper case.
+	Uppercase bool
+	// BoldLabel and BoldMessage render the first and remaining segments in bold.
+	BoldLabel   bool
+	BoldMessage bool
+	// LogoColor is the default color of SVG logos.
+	LogoColor string
+}
+
+//nolint:gochecknoglobals,gomnd
+var styleSpecs = map[Style]styleSpec{
+	StyleFlat:        {Height: 20, FontScale: 1, Padding: 1, LogoColor: "#fff"},
+	StyleFlatSquare:  {Height: 20, FontScale: 1, Padding: 1, LogoColor: "#fff"},
+	StylePlastic:     {Height: 18, FontScale: 1, Padding: 1, LogoColor: "#fff"},
+	StyleForTheBadge: {Height: 28, FontScale: 10.0 / 11.0, Padding: 1.5, LetterSpacing: 1.25, Uppercase: true, BoldMessage: true, LogoColor: "#fff"},
+	StyleSocial:      {Height: 20, FontScale: 1, Padding: 0.75, Gap: 6, BoldLabel: true, BoldMessage: true, LogoColor: "#333"},
+}
+
+// Styles lists the supported badge styles.
+func Styles() []Style {
+	return []Style{StyleFlat, StyleFlatSquare, StylePlastic, StyleForTheBadge, StyleSocial}
+}
+
+// ParseStyle returns the Style with the given name.
+func ParseStyle(name string) (Style, error) {
+	style := Style(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := styleSpecs[style]; !ok {
+		return "", errors.Wrapf(ErrUnknownStyle, "%q", name)
+	}
+	return style, nil
+}
+
+// templateName returns the asset path of the template for the style.
+func (s Style) templateName() string {
+	return fmt.Sprintf("badges/%s.svg.p2", s)
+}
+
+// badgeSegment is a section of a badge to be laid out.
+type badgeSegment struct {
+	Text  string
+	Color color.NRGBA
+	// Label is set for the label segment, which badges without a label leave out.
+	Label bool
+}
+
+// layoutSegment is a badge segment positioned for rendering.
+type layoutSegment struct {
+	// X is the left edge of the segment and Width its total width.
+	X     int
+	Width int
+	// TextX is the horizontal center of the text and TextWidth its measured width.
+	TextX     int
+	TextWidth int
+	Text      string
+	// Color is the CSS color of the segment and fill its parsed value.
+	Color string
+	Bold  bool
+
+	fill color.NRGBA
+}
+
+// badgeLayout is a badge with all segments positioned, ready to be rendered by a style template.
+type badgeLayout struct {
+	Width         int
+	Height        int
+	LetterSpacing float64
+	// FontSize and FontFamily select the font of the text, and TextY is the baseline of the text.
+	FontSize   float64
+	FontFamily string
+	TextY      int
+	// ArrowY is the top of the arrow joining segments in the social style.
+	ArrowY   int
+	Segments []layoutSegment
+	// Logo is nil if the badge has no logo.
+	Logo *layoutLogo
+
+	font *badgeFont
+}
+
+// layout positions segments according to the style using font to measure text. If logoWidth is
+// not zero, space for a logo is made at the start of the first segment.
+func (bs *badgeService) layout(style Style, font *badgeFont, segments []badgeSegment, logoWidth int) badgeLayout {
+	spec := styleSpecs[style]
+	fontSize := bs.config.FontSize * spec.FontScale
+	padding := int(math.Round(spec.Padding * float64(bs.config.XSpacing)))
+	sizeRatio := bs.config.FontSize / referenceFontSize
+	height := int(math.Round(float64(spec.Height) * sizeRatio))
+
+	result := badgeLayout{
+		Height:        height,
+		LetterSpacing: spec.LetterSpacing,
+		FontSize:      math.Round(fontSize*100) / 100, //nolint:gomnd
+		FontFamily:    font.family,
+		// Capital letters are centered vertically.
+		TextY:    int(math.Round((float64(height) + font.calc.CapHeight(fontSize)) / 2)), //nolint:gomnd
+		ArrowY:   height/2 - arrowTop,                                                    //nolint:gomnd
+		Segments: make([]layoutSegment, 0, len(segments)),
+		font:     font,
+	}
+
+	x := 0
+	for idx, segment := range segments {
+		text := lo.Ternary(spec.Uppercase, strings.ToUpper(segment.Text), segment.Text)
+		bold := lo.Ternary(segment.Label, spec.BoldLabel, spec.BoldMessage)
+
+		textWidth, _ := font.calc.TextWidth(fontSize, text)
+		if bold {
+			textWidth = int(math.Round(float64(textWidth) * boldWidthFactor))
+		}
+		textWidth += int(math.Round(spec.LetterSpacing * float64(utf8.RuneCountInString(text))))
+
+		if idx > 0 {
+			x += spec.Gap
+		}
+
+		// The logo offsets the text of the first segment.
+		textOffset := 0
+		if idx == 0 && logoWidth > 0 {
+			scaledLogoHeight := int(math.Round(logoHeight * sizeRatio))
+			result.Logo = &layoutLogo{
+				X:      x + padding,
+				Y:      (height - scaledLogoHeight) / 2, //nolint:gomnd
+				Width:  logoWidth,
+				Height: scaledLogoHeight,
+			}
+			textOffset = logoWidth + lo.Ternary(text != "", logoSpacing, 0)
+		}
+
+		width := textWidth + textOffset + 2*padding
+		result.Segments = append(result.Segments, layoutSegment{
+			X:         x,
+			Width:     width,
+			TextX:     x + padding + textOffset + textWidth/2,
+			TextWidth: textWidth,
+			Text:      text,
+			Color:     cssColor(segment.Color),
+			Bold:      bold,
+			fill:      segment.Color,
+		})
+		x += width
+	}
+	result.Width = x
+
+	return result
+}
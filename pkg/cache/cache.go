@@ -0,0 +1,130 @@
+// Package cache implements the in-process response cache used for dynamic badge targets.
+package cache
+
+import (
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/labstack/echo-contrib/prometheus"
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultStale = "stale"
+)
+
+// Config configures the response cache.
+type Config struct {
+	TTL        time.Duration `help:"Default time to cache dynamic badge target responses (0 disables caching)" default:"30s"`
+	MaxStale   time.Duration `help:"Time expired responses are kept to serve while revalidating or when the target fails (0 disables)" default:"24h"`
+	MaxEntries int           `help:"Maximum number of target responses to keep in the cache" default:"1000"`
+}
+
+// entry is a cached value and the time it expires.
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache is a size-bounded cache with per-entry expiry. Least recently used entries
+// are evicted once the cache is full.
+type Cache[V any] struct {
+	lru        *lru.Cache[string, entry[V]]
+	defaultTTL time.Duration
+	maxStale   time.Duration
+	requests   *prometheus.Metric
+}
+
+// New initializes a new Cache from the supplied configuration.
+func New[V any](config Config) (*Cache[V], error) {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1
+	}
+
+	entries, err := lru.New[string, entry[V]](config.MaxEntries)
+	if err != nil {
+		return nil, errors.Wrap(err, "cache.New")
+	}
+
+	return &Cache[V]{
+		lru:        entries,
+		defaultTTL: config.TTL,
+		maxStale:   config.MaxStale,
+		requests: &prometheus.Metric{
+			ID:          "dynamicCacheRequests",
+			Name:        "dynamic_cache_requests_total",
+			Description: "How many dynamic badge target lookups were served from the response cache, partitioned by result.",
+			Type:        "counter_vec",
+			Args:        []string{"result"},
+		},
+	}, nil
+}
+
+// Metrics returns the custom metrics for the cache. They must be registered with the
+// Prometheus middleware for the cache to report hit and miss counts.
+func (c *Cache[V]) Metrics() []*prometheus.Metric {
+	return []*prometheus.Metric{c.requests}
+}
+
+// DefaultTTL returns the configured default time to live for entries.
+func (c *Cache[V]) DefaultTTL() time.Duration {
+	return c.defaultTTL
+}
+
+// Get returns the cached value for key if it exists and has not expired. Lookups of expired
+// entries are not counted, as GetStale counts them as stale or missed.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	var empty V
+	cached, ok := c.lru.Get(key)
+	if !ok {
+		c.observe(resultMiss)
+		return empty, false
+	}
+	if time.Now().After(cached.expires) {
+		return empty, false
+	}
+
+	c.observe(resultHit)
+	return cached.value, true
+}
+
+// GetStale returns the cached value for key if it has expired less than the configured
+// max stale time ago. It is intended to be called after Get has missed.
+func (c *Cache[V]) GetStale(key string) (V, bool) {
+	cached, ok := c.lru.Peek(key)
+	if !ok || time.Now().After(cached.expires.Add(c.maxStale)) {
+		// Get has already counted the lookup if there was no entry.
+		if ok {
+			c.observe(resultMiss)
+		}
+		var empty V
+		return empty, false
+	}
+
+	c.observe(resultStale)
+	return cached.value, true
+}
+
+// Set stores value under key for the supplied ttl. A zero or negative ttl disables caching
+// of the value, so any value already stored under key is removed and nothing is served stale.
+func (c *Cache[V]) Set(key string, value V, ttl time.Duration) {
+	if ttl <= 0 {
+		c.lru.Remove(key)
+		return
+	}
+
+	c.lru.Add(key, entry[V]{
+		value:   value,
+		expires: time.Now().Add(ttl),
+	})
+}
+
+// observe increments the request counter if it has been registered.
+func (c *Cache[V]) observe(result string) {
+	if counter, ok := c.requests.MetricCollector.(*prom.CounterVec); ok {
+		counter.WithLabelValues(result).Inc()
+	}
+}
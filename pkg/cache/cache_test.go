@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// expired is long enough for entries with a TTL or max stale time of a millisecond to expire.
+const expired = 10 * time.Millisecond
+
+func newTestCache(t *testing.T, maxStale time.Duration) (*Cache[string], *prom.CounterVec) {
+	t.Helper()
+	c, err := New[string](Config{TTL: time.Hour, MaxStale: maxStale, MaxEntries: 10})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	counter := prom.NewCounterVec(prom.CounterOpts{Name: "test_requests_total"}, []string{"result"})
+	c.requests.MetricCollector = counter
+	return c, counter
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ttl      time.Duration
+		maxStale time.Duration
+		set      bool
+		hit      bool
+		stale    bool
+		results  map[string]float64
+	}{
+		{
+			name:    "missing",
+			results: map[string]float64{resultMiss: 1},
+		},
+		{
+			name:    "fresh",
+			ttl:     time.Hour,
+			set:     true,
+			hit:     true,
+			results: map[string]float64{resultHit: 1},
+		},
+		{
+			name:     "expired within the stale window",
+			ttl:      time.Millisecond,
+			maxStale: time.Hour,
+			set:      true,
+			stale:    true,
+			results:  map[string]float64{resultStale: 1},
+		},
+		{
+			name:     "expired beyond the stale window",
+			ttl:      time.Millisecond,
+			maxStale: time.Millisecond,
+			set:      true,
+			results:  map[string]float64{resultMiss: 1},
+		},
+		{
+			name:    "stale serving disabled",
+			ttl:     time.Millisecond,
+			set:     true,
+			results: map[string]float64{resultMiss: 1},
+		},
+		{
+			name:     "zero ttl is not cached",
+			maxStale: time.Hour,
+			set:      true,
+			results:  map[string]float64{resultMiss: 1},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			c, counter := newTestCache(t, test.maxStale)
+			if test.set {
+				c.Set("key", "value", test.ttl)
+				time.Sleep(expired)
+			}
+
+			value, hit := c.Get("key")
+			if hit != test.hit || (hit && value != "value") {
+				t.Errorf("Get = %q, %v, want hit %v", value, hit, test.hit)
+			}
+			if !hit {
+				value, stale := c.GetStale("key")
+				if stale != test.stale || (stale && value != "value") {
+					t.Errorf("GetStale = %q, %v, want stale %v", value, stale, test.stale)
+				}
+			}
+
+			for _, result := range []string{resultHit, resultMiss, resultStale} {
+				if got := testutil.ToFloat64(counter.WithLabelValues(result)); got != test.results[result] {
+					t.Errorf("%s count = %v, want %v", result, got, test.results[result])
+				}
+			}
+		})
+	}
+}
+
+func TestCacheSetRemoves(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCache(t, time.Hour)
+	c.Set("key", "value", time.Hour)
+	c.Set("key", "value", 0)
+
+	if _, hit := c.Get("key"); hit {
+		t.Errorf("Get hit an entry set with a zero ttl")
+	}
+	if _, stale := c.GetStale("key"); stale {
+		t.Errorf("GetStale served an entry set with a zero ttl")
+	}
+}
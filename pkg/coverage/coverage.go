@@ -0,0 +1,163 @@
+// Package coverage computes line coverage from the coverage reports of common test tools.
+package coverage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown coverage report format")
+	ErrParseFailed   = errors.New("coverage report could not be parsed")
+	ErrNoLines       = errors.New("coverage report has no lines")
+)
+
+// Format is a supported coverage report format.
+type Format string
+
+const (
+	// Auto selects the format from the content of the report.
+	Auto Format = "auto"
+	// Go is a Go cover profile, as written by go test -coverprofile.
+	Go        Format = "go"
+	LCOV      Format = "lcov"
+	Cobertura Format = "cobertura"
+	JaCoCo    Format = "jacoco"
+)
+
+// Formats lists the accepted format names.
+var Formats = []Format{Auto, Go, LCOV, Cobertura, JaCoCo} //nolint:gochecknoglobals
+
+// Parse returns the Format with the given name. An empty name is Auto.
+func Parse(name string) (Format, error) {
+	if name == "" {
+		return Auto, nil
+	}
+	format := Format(strings.ToLower(name))
+	if !lo.Contains(Formats, format) {
+		return "", errors.Wrapf(ErrUnknownFormat, "%q", name)
+	}
+	return format, nil
+}
+
+// Report is the line coverage of a coverage report.
+type Report struct {
+	// Lines is the number of lines which can be covered, and Covered how many of them are.
+	Lines   int64
+	Covered int64
+}
+
+// Percent returns the percentage of lines covered.
+func (r Report) Percent() float64 {
+	if r.Lines == 0 {
+		return 0
+	}
+	return float64(r.Covered) * 100 / float64(r.Lines) //nolint:gomnd
+}
+
+// lineSet counts the lines of a report. Lines reported more than once, such as by several test
+// runs, are covered if any of them is.
+type lineSet map[string]map[int64]bool
+
+func (s lineSet) add(file string, line int64, covered bool) {
+	if s[file] == nil {
+		s[file] = map[int64]bool{}
+	}
+	s[file][line] = s[file][line] || covered
+}
+
+func (s lineSet) report() Report {
+	report := Report{}
+	for _, lines := range s {
+		for _, covered := range lines {
+			report.Lines++
+			if covered {
+				report.Covered++
+			}
+		}
+	}
+	return report
+}
+
+// Detect returns the format of a report from its content.
+func Detect(data []byte) (Format, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return Go, nil
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		root, err := xmlRoot(trimmed)
+		if err != nil {
+			return "", err
+		}
+		switch root {
+		case "coverage":
+			return Cobertura, nil
+		case "report":
+			return JaCoCo, nil
+		default:
+			return "", errors.Wrapf(ErrUnknownFormat, "XML report with root element %q", root)
+		}
+	case bytes.HasPrefix(trimmed, []byte("TN:")) || bytes.HasPrefix(trimmed, []byte("SF:")):
+		return LCOV, nil
+	default:
+		return "", errors.Wrap(ErrUnknownFormat, "report format could not be detected")
+	}
+}
+
+// Compute returns the line coverage of a report. Auto detects the format of the report.
+func Compute(format Format, data []byte) (Report, error) {
+	var err error
+	if format == Auto {
+		if format, err = Detect(data); err != nil {
+			return Report{}, err
+		}
+	}
+
+	var report Report
+	switch format {
+	case Go:
+		report, err = parseGo(data)
+	case LCOV:
+		report, err = parseLCOV(data)
+	case Cobertura:
+		report, err = parseCobertura(data)
+	case JaCoCo:
+		report, err = parseJaCoCo(data)
+	case Auto:
+		return Report{}, errors.Wrap(ErrUnknownFormat, "Compute: BUG - auto format was not resolved")
+	default:
+		return Report{}, errors.Wrapf(ErrUnknownFormat, "%q", format)
+	}
+
+	if err != nil {
+		return Report{}, errors.Wrapf(ErrParseFailed, "%s: %s", format, err.Error())
+	}
+	if report.Lines == 0 {
+		return Report{}, errors.Wrapf(ErrNoLines, "%s", format)
+	}
+	return report, nil
+}
+
+// xmlRoot returns the name of the root element of an XML document.
+func xmlRoot(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return "", errors.Wrap(ErrParseFailed, "XML report has no root element")
+		}
+		if err != nil {
+			return "", errors.Wrapf(ErrParseFailed, "XML report: %s", err.Error())
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
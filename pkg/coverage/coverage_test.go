@@ -0,0 +1,133 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format Format
+		report string
+		want   Report
+		err    error
+	}{
+		{
+			name:   "go profile",
+			format: Go,
+			report: "mode: set\n" +
+				"example.com/pkg/a.go:3.10,5.2 2 1\n" +
+				"example.com/pkg/a.go:7.10,8.2 1 0\n",
+			want: Report{Lines: 5, Covered: 3},
+		},
+		{
+			name:   "go profile lines covered by any block",
+			format: Go,
+			report: "mode: count\n" +
+				"C:/src/a.go:1.1,2.2 1 0\n" +
+				"C:/src/a.go:2.3,3.2 1 4\n",
+			want: Report{Lines: 3, Covered: 2},
+		},
+		{
+			name:   "go profile block too long",
+			format: Go,
+			report: "a.go:1.1,9999999999.1 1 1\n",
+			err:    ErrParseFailed,
+		},
+		{
+			name:   "go profile block ends before it starts",
+			format: Go,
+			report: "a.go:10.1,2.1 1 1\n",
+			err:    ErrParseFailed,
+		},
+		{
+			name:   "go profile blocks longer than the profile",
+			format: Go,
+			report: strings.Repeat("a.go:1.1,900000.1 1 1\n", 3),
+			err:    ErrParseFailed,
+		},
+		{
+			name:   "go profile invalid block",
+			format: Go,
+			report: "a.go:1.1 1 1\n",
+			err:    ErrParseFailed,
+		},
+		{
+			name:   "lcov",
+			format: LCOV,
+			report: "TN:\nSF:a.c\nDA:1,1\nDA:2,0\nDA:3,2\nend_of_record\n" +
+				"SF:b.c\nLF:10\nLH:5\nend_of_record\n",
+			want: Report{Lines: 13, Covered: 7},
+		},
+		{
+			name:   "lcov invalid DA record",
+			format: LCOV,
+			report: "SF:a.c\nDA:x\nend_of_record\n",
+			err:    ErrParseFailed,
+		},
+		{
+			name:   "cobertura lines",
+			format: Cobertura,
+			report: `<?xml version="1.0"?><coverage lines-valid="99" lines-covered="1"><packages><package>` +
+				`<classes><class filename="a.py"><lines><line number="1" hits="1"/><line number="2" hits="0"/>` +
+				`</lines></class></classes></package></packages></coverage>`,
+			want: Report{Lines: 2, Covered: 1},
+		},
+		{
+			name:   "cobertura summary",
+			format: Cobertura,
+			report: `<coverage lines-valid="40" lines-covered="30"><packages/></coverage>`,
+			want:   Report{Lines: 40, Covered: 30},
+		},
+		{
+			name:   "jacoco",
+			format: JaCoCo,
+			report: `<report name="x"><package name="p"><counter type="LINE" missed="100" covered="100"/></package>` +
+				`<counter type="INSTRUCTION" missed="1" covered="1"/><counter type="LINE" missed="5" covered="15"/></report>`,
+			want: Report{Lines: 20, Covered: 15},
+		},
+		{
+			name:   "jacoco without line counter",
+			format: JaCoCo,
+			report: `<report name="x"></report>`,
+			err:    ErrParseFailed,
+		},
+		{
+			name:   "auto detects go",
+			format: Auto,
+			report: "mode: atomic\na.go:1.1,1.10 1 1\n",
+			want:   Report{Lines: 1, Covered: 1},
+		},
+		{
+			name:   "no lines",
+			format: Go,
+			report: "mode: set\n",
+			err:    ErrNoLines,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			report, err := Compute(test.format, []byte(test.report))
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("Compute error = %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compute: %v", err)
+			}
+			if report != test.want {
+				t.Errorf("Compute = %+v, want %+v", report, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxBlockLines is the most lines a Go cover profile block may span. Blocks are expanded into their lines, so
+// a forged block spanning billions of lines would otherwise exhaust the server.
+const maxBlockLines = 1000000
+
+// parseGo parses a Go cover profile. Each block is "file:startLine.startCol,endLine.endCol statements count",
+// and a line is covered if any block on it ran. Blocks may span at most maxBlockLines, and all the blocks of
+// a profile at most maxBlockLines or the size of the profile in bytes, whichever is larger.
+func parseGo(data []byte) (Report, error) {
+	lines := lineSet{}
+	remainingLines := int64(len(data))
+	if remainingLines < maxBlockLines {
+		remainingLines = maxBlockLines
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*16) //nolint:gomnd
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		// File names may contain colons, so the block is split from the end.
+		sep := strings.LastIndex(line, ":")
+		if sep < 0 {
+			return Report{}, errors.Errorf("line %d: missing block position", lineNum)
+		}
+		file := line[:sep]
+		fields := strings.Fields(line[sep+1:])
+		if len(fields) != 3 { //nolint:gomnd
+			return Report{}, errors.Errorf("line %d: expected position, statements and count", lineNum)
+		}
+
+		start, end, found := strings.Cut(fields[0], ",")
+		if !found {
+			return Report{}, errors.Errorf("line %d: invalid block position %q", lineNum, fields[0])
+		}
+		startLine, err := goPositionLine(start)
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "line %d", lineNum)
+		}
+		endLine, err := goPositionLine(end)
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "line %d", lineNum)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "line %d: invalid count", lineNum)
+		}
+
+		span := endLine - startLine + 1
+		if startLine < 1 || span < 1 || span > maxBlockLines {
+			return Report{}, errors.Errorf("line %d: invalid block lines %d to %d", lineNum, startLine, endLine)
+		}
+		if remainingLines -= span; remainingLines < 0 {
+			return Report{}, errors.Errorf("line %d: blocks span more lines than the profile can describe", lineNum)
+		}
+
+		for blockLine := startLine; blockLine <= endLine; blockLine++ {
+			lines.add(file, blockLine, count > 0)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Report{}, errors.Wrap(err, "parseGo")
+	}
+	return lines.report(), nil
+}
+
+// goPositionLine returns the line of a line.column cover profile position.
+func goPositionLine(position string) (int64, error) {
+	line, _, _ := strings.Cut(position, ".")
+	value, err := strconv.ParseInt(line, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid block position %q", position)
+	}
+	return value, nil
+}
+
+// parseLCOV parses an lcov tracefile. Lines are read from the DA records of each source file.
+// Files which only have LF and LH summary records are counted from those.
+func parseLCOV(data []byte) (Report, error) {
+	lines := lineSet{}
+	summary := Report{}
+
+	file := ""
+	hasLines := false
+	fileSummary := Report{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*16) //nolint:gomnd
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		record, value, _ := strings.Cut(line, ":")
+
+		var err error
+		switch record {
+		case "SF":
+			file, hasLines, fileSummary = value, false, Report{}
+		case "DA":
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 { //nolint:gomnd
+				return Report{}, errors.Errorf("line %d: invalid DA record", lineNum)
+			}
+			lineNo, lineErr := strconv.ParseInt(fields[0], 10, 64)
+			// Some tools write fractional or negative hit counts, so only whether the line ran is used.
+			hits, hitsErr := strconv.ParseFloat(fields[1], 64)
+			if lineErr != nil || hitsErr != nil {
+				return Report{}, errors.Errorf("line %d: invalid DA record", lineNum)
+			}
+			lines.add(file, lineNo, hits > 0)
+			hasLines = true
+		case "LF":
+			fileSummary.Lines, err = strconv.ParseInt(value, 10, 64)
+		case "LH":
+			fileSummary.Covered, err = strconv.ParseInt(value, 10, 64)
+		case "end_of_record":
+			if !hasLines {
+				summary.Lines += fileSummary.Lines
+				summary.Covered += fileSummary.Covered
+			}
+			file, hasLines, fileSummary = "", false, Report{}
+		}
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "line %d: invalid %s record", lineNum, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Report{}, errors.Wrap(err, "parseLCOV")
+	}
+
+	report := lines.report()
+	report.Lines += summary.Lines
+	report.Covered += summary.Covered
+	return report, nil
+}
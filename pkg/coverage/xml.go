@@ -0,0 +1,143 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// xmlAttr returns the value of the named attribute of an element.
+func xmlAttr(start xml.StartElement, name string) (string, bool) {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// xmlIntAttr returns the value of the named integer attribute of an element.
+func xmlIntAttr(start xml.StartElement, name string) (int64, error) {
+	value, ok := xmlAttr(start, name)
+	if !ok {
+		return 0, errors.Errorf("<%s> has no %s attribute", start.Name.Local, name)
+	}
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "<%s> has invalid %s attribute", start.Name.Local, name)
+	}
+	return result, nil
+}
+
+// walkXML calls fn with each start element of a document and its depth, the root being depth 1. Reports
+// can be large, so they are streamed instead of decoded into structures.
+func walkXML(data []byte, fn func(start xml.StartElement, depth int) error) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "XML report")
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+			if err := fn(element, depth); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+// parseCobertura parses a Cobertura XML report. Lines are read from the line elements of each class. Reports
+// without line elements are counted from the lines-valid and lines-covered attributes of the root.
+func parseCobertura(data []byte) (Report, error) {
+	lines := lineSet{}
+	summary := Report{}
+	file := ""
+
+	err := walkXML(data, func(start xml.StartElement, depth int) error {
+		var err error
+		switch start.Name.Local {
+		case "coverage":
+			if depth != 1 {
+				return nil
+			}
+			if _, ok := xmlAttr(start, "lines-valid"); !ok {
+				return nil
+			}
+			if summary.Lines, err = xmlIntAttr(start, "lines-valid"); err != nil {
+				return err
+			}
+			summary.Covered, err = xmlIntAttr(start, "lines-covered")
+			return err
+		case "class":
+			file, _ = xmlAttr(start, "filename")
+		case "line":
+			number, err := xmlIntAttr(start, "number")
+			if err != nil {
+				return err
+			}
+			hits, err := xmlIntAttr(start, "hits")
+			if err != nil {
+				return err
+			}
+			lines.add(file, number, hits > 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+
+	if report := lines.report(); report.Lines > 0 {
+		return report, nil
+	}
+	return summary, nil
+}
+
+// parseJaCoCo parses a JaCoCo XML report from the LINE counter of the report element, which totals
+// the counters of every package.
+func parseJaCoCo(data []byte) (Report, error) {
+	report := Report{}
+	found := false
+
+	err := walkXML(data, func(start xml.StartElement, depth int) error {
+		if start.Name.Local != "counter" || depth != 2 { //nolint:gomnd
+			return nil
+		}
+		if counterType, _ := xmlAttr(start, "type"); counterType != "LINE" {
+			return nil
+		}
+
+		missed, err := xmlIntAttr(start, "missed")
+		if err != nil {
+			return err
+		}
+		covered, err := xmlIntAttr(start, "covered")
+		if err != nil {
+			return err
+		}
+		report = Report{Lines: missed + covered, Covered: covered}
+		found = true
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	if !found {
+		return Report{}, errors.New("report has no LINE counter")
+	}
+	return report, nil
+}
@@ -0,0 +1,153 @@
+// Package dataformat decodes badge target responses in the supported data formats into
+// generic values for use in badge templates.
+package dataformat
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown data format")
+	ErrDecodeFailed  = errors.New("response could not be decoded")
+)
+
+// Format is a supported target response data format.
+type Format string
+
+const (
+	// Auto selects the format from the Content-Type of the response.
+	Auto       Format = "auto"
+	JSON       Format = "json"
+	YAML       Format = "yaml"
+	TOML       Format = "toml"
+	XML        Format = "xml"
+	Text       Format = "text"
+	Prometheus Format = "prometheus"
+)
+
+// Formats lists the accepted format names.
+var Formats = []Format{Auto, JSON, YAML, TOML, XML, Text, Prometheus} //nolint:gochecknoglobals
+
+// Parse returns the Format with the given name. An empty name is Auto.
+func Parse(name string) (Format, error) {
+	if name == "" {
+		return Auto, nil
+	}
+	format := Format(strings.ToLower(name))
+	if !lo.Contains(Formats, format) {
+		return "", errors.Wrapf(ErrUnknownFormat, "%q", name)
+	}
+	return format, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler so formats can be set from configuration.
+func (f *Format) UnmarshalText(text []byte) error {
+	format, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// Detect returns the format for a Content-Type header value. Unrecognized or missing content
+// types are treated as JSON. Other text types are Text, which DecodeAuto tries to decode as a
+// structured format first.
+func Detect(contentType string) Format {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return JSON
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return JSON
+	case lo.Contains([]string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"}, mediaType):
+		return YAML
+	case lo.Contains([]string{"application/toml", "text/toml", "text/x-toml"}, mediaType):
+		return TOML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return XML
+	case mediaType == "application/openmetrics-text":
+		return Prometheus
+	case mediaType == "text/plain" && params["version"] == "0.0.4":
+		// The Prometheus text exposition format is served as text/plain with a version parameter.
+		return Prometheus
+	case strings.HasPrefix(mediaType, "text/"):
+		return Text
+	default:
+		return JSON
+	}
+}
+
+// DecodeAuto decodes data in the format detected from its Content-Type. Many services serve
+// structured data as text/plain, so Text responses are decoded as JSON, or as YAML or TOML if that
+// gives a map or list, and only as text if none of them do. The format used is returned.
+func DecodeAuto(contentType string, data []byte) (interface{}, Format, error) {
+	format := Detect(contentType)
+	if format != Text {
+		result, err := Decode(format, data)
+		return result, format, err
+	}
+
+	if result, err := Decode(JSON, data); err == nil {
+		return result, JSON, nil
+	}
+	// Almost any text is a YAML scalar, so only structured results are taken.
+	for _, structured := range []Format{YAML, TOML} {
+		if result, err := Decode(structured, data); err == nil && isStructured(result) {
+			return result, structured, nil
+		}
+	}
+	result, err := Decode(Text, data)
+	return result, Text, err
+}
+
+// isStructured returns true if a decoded value is a map or list.
+func isStructured(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Decode decodes data in the given format. Auto must be resolved with Detect first.
+func Decode(format Format, data []byte) (interface{}, error) {
+	var (
+		result interface{}
+		err    error
+	)
+
+	switch format {
+	case JSON:
+		err = json.Unmarshal(data, &result)
+	case YAML:
+		err = yaml.Unmarshal(data, &result)
+	case TOML:
+		err = toml.Unmarshal(data, &result)
+	case XML:
+		result, err = decodeXML(data)
+	case Text:
+		result = strings.TrimSpace(string(data))
+	case Prometheus:
+		result, err = decodePrometheus(data)
+	case Auto:
+		return nil, errors.Wrap(ErrUnknownFormat, "Decode: auto format must be resolved before decoding")
+	default:
+		return nil, errors.Wrapf(ErrUnknownFormat, "%q", format)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(ErrDecodeFailed, "%s: %s", format, err.Error())
+	}
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package dataformat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeAuto(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        string
+		format      Format
+		want        interface{}
+	}{
+		{"json", "application/json", `{"a": 1}`, JSON, map[string]interface{}{"a": 1.0}},
+		{"missing content type", "", `[1]`, JSON, []interface{}{1.0}},
+		{"json as text", "text/plain; charset=utf-8", `{"a": "b"}`, JSON, map[string]interface{}{"a": "b"}},
+		{"yaml as text", "text/plain", "a:\n  b: c\n", YAML, map[string]interface{}{"a": map[string]interface{}{"b": "c"}}},
+		{"toml as text", "text/plain", "a = \"b\"\n", TOML, map[string]interface{}{"a": "b"}},
+		{"text", "text/plain", " v1.2.3\n", Text, "v1.2.3"},
+		{"explicit yaml", "application/yaml", "v1.2.3", YAML, "v1.2.3"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			result, format, err := DecodeAuto(test.contentType, []byte(test.data))
+			if err != nil {
+				t.Fatalf("DecodeAuto: %v", err)
+			}
+			if format != test.format {
+				t.Errorf("DecodeAuto format = %s, want %s", format, test.format)
+			}
+			if !reflect.DeepEqual(result, test.want) {
+				t.Errorf("DecodeAuto = %#v, want %#v", result, test.want)
+			}
+		})
+	}
+}
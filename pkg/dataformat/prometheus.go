@@ -0,0 +1,60 @@
+package dataformat
+
+import (
+	"bytes"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/samber/lo"
+)
+
+// decodePrometheus decodes the Prometheus text exposition format into a map of metric family
+// names to their list of samples. Each sample is a map with "labels" and, depending on the
+// metric type, "value" or "count", "sum" and "buckets" or "quantiles".
+func decodePrometheus(data []byte) (interface{}, error) {
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return lo.MapValues(families, func(family *dto.MetricFamily, _ string) interface{} {
+		return lo.Map(family.GetMetric(), func(metric *dto.Metric, _ int) interface{} {
+			return decodePrometheusSample(family.GetType(), metric)
+		})
+	}), nil
+}
+
+func decodePrometheusSample(metricType dto.MetricType, metric *dto.Metric) map[string]interface{} {
+	sample := map[string]interface{}{
+		"labels": lo.SliceToMap(metric.GetLabel(), func(label *dto.LabelPair) (string, interface{}) {
+			return label.GetName(), label.GetValue()
+		}),
+	}
+
+	switch metricType {
+	case dto.MetricType_COUNTER:
+		sample["value"] = metric.GetCounter().GetValue()
+	case dto.MetricType_GAUGE:
+		sample["value"] = metric.GetGauge().GetValue()
+	case dto.MetricType_UNTYPED:
+		sample["value"] = metric.GetUntyped().GetValue()
+	case dto.MetricType_SUMMARY:
+		summary := metric.GetSummary()
+		sample["count"] = summary.GetSampleCount()
+		sample["sum"] = summary.GetSampleSum()
+		sample["quantiles"] = lo.SliceToMap(summary.GetQuantile(), func(quantile *dto.Quantile) (string, interface{}) {
+			return strconv.FormatFloat(quantile.GetQuantile(), 'g', -1, 64), quantile.GetValue()
+		})
+	case dto.MetricType_HISTOGRAM:
+		histogram := metric.GetHistogram()
+		sample["count"] = histogram.GetSampleCount()
+		sample["sum"] = histogram.GetSampleSum()
+		sample["buckets"] = lo.SliceToMap(histogram.GetBucket(), func(bucket *dto.Bucket) (string, interface{}) {
+			return strconv.FormatFloat(bucket.GetUpperBound(), 'g', -1, 64), bucket.GetCumulativeCount()
+		})
+	}
+
+	return sample
+}
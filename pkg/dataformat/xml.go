@@ -0,0 +1,100 @@
+package dataformat
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// xmlAttrPrefix is prepended to attribute names so they don't collide with child elements.
+	xmlAttrPrefix = "_"
+	// xmlTextKey holds the character data of elements which also have attributes or children.
+	xmlTextKey = "_text"
+)
+
+var (
+	ErrNoXMLRoot = errors.New("XML document has no root element")
+)
+
+// decodeXML decodes an XML document into nested maps. The result is a map holding the root
+// element under its name.
+//
+// Elements with only character data decode to a string. Other elements decode to a map of
+// attributes (prefixed with an underscore), child elements and any character data under
+// "_text". Repeated child elements decode to a list.
+func decodeXML(data []byte) (interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, ErrNoXMLRoot
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "decodeXML")
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			root, err := decodeXMLElement(decoder, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: root}, nil
+		}
+	}
+}
+
+// decodeXMLElement decodes the element opened by start, consuming tokens up to its end.
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	element := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		element[xmlAttrPrefix+attr.Name.Local] = attr.Value
+	}
+
+	text := strings.Builder{}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, errors.Wrap(err, "decodeXMLElement")
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, token)
+			if err != nil {
+				return nil, err
+			}
+			addXMLChild(element, token.Name.Local, child)
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(element) == 0 {
+				return content, nil
+			}
+			if content != "" {
+				element[xmlTextKey] = content
+			}
+			return element, nil
+		}
+	}
+}
+
+// addXMLChild adds a child element value, converting repeated elements to a list.
+func addXMLChild(element map[string]interface{}, name string, child interface{}) {
+	existing, ok := element[name]
+	if !ok {
+		element[name] = child
+		return
+	}
+	if list, ok := existing.([]interface{}); ok {
+		element[name] = append(list, child)
+		return
+	}
+	element[name] = []interface{}{existing, child}
+}
@@ -0,0 +1,282 @@
+// Package netfilter restricts which destinations outbound requests for badge targets may connect to.
+package netfilter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrDestinationDenied = errors.New("destination denied by target filter")
+	ErrInvalidRule       = errors.New("invalid target filter rule")
+)
+
+// Config configures the outbound target filter. Deny rules always take precedence. If any
+// allow rules for hosts or networks are set, a target must match at least one of them.
+type Config struct {
+	AllowHosts   []string `help:"Host globs targets may use (e.g. *.example.com)"`
+	DenyHosts    []string `help:"Host globs targets may not use"`
+	AllowCIDRs   []string `name:"allow-cidrs" help:"Networks target hosts may resolve to"`
+	DenyCIDRs    []string `name:"deny-cidrs" help:"Networks target hosts may not resolve to" default:"0.0.0.0/8,10.0.0.0/8,100.64.0.0/10,127.0.0.0/8,169.254.0.0/16,172.16.0.0/12,192.168.0.0/16,::/128,::1/128,fc00::/7,fe80::/10"`
+	AllowSchemes []string `help:"URL schemes targets may use" default:"http,https"`
+	AllowPorts   []int    `help:"Ports targets may use (empty allows any port)"`
+	DenyPorts    []int    `help:"Ports targets may not use"`
+}
+
+// contextKey is the type of context values set by the filter.
+type contextKey int
+
+const (
+	// hostAllowedKey marks a request whose host matched an allow rule.
+	hostAllowedKey contextKey = iota
+	// proxiedKey marks a request which is sent via a proxy.
+	proxiedKey
+)
+
+// Filter checks outbound requests against the configured rules. Scheme, port and host rules are
+// checked for every request (including redirects), and network rules are checked against the
+// resolved addresses when connections are dialled.
+type Filter struct {
+	allowHosts   []string
+	denyHosts    []string
+	allowNets    []*net.IPNet
+	denyNets     []*net.IPNet
+	allowSchemes []string
+	allowPorts   []int
+	denyPorts    []int
+
+	resolver *net.Resolver
+	dialer   *net.Dialer
+}
+
+// New initializes a Filter from the supplied configuration.
+func New(config Config) (*Filter, error) {
+	allowNets, err := parseCIDRs(config.AllowCIDRs)
+	if err != nil {
+		return nil, errors.Wrap(err, "netfilter.New: allow-cidrs")
+	}
+	denyNets, err := parseCIDRs(config.DenyCIDRs)
+	if err != nil {
+		return nil, errors.Wrap(err, "netfilter.New: deny-cidrs")
+	}
+
+	normalize := func(values []string) []string {
+		return lo.FilterMap(values, func(value string, _ int) (string, bool) {
+			value = strings.ToLower(strings.TrimSpace(value))
+			return value, value != ""
+		})
+	}
+
+	return &Filter{
+		allowHosts:   normalize(config.AllowHosts),
+		denyHosts:    normalize(config.DenyHosts),
+		allowNets:    allowNets,
+		denyNets:     denyNets,
+		allowSchemes: normalize(config.AllowSchemes),
+		allowPorts:   config.AllowPorts,
+		denyPorts:    config.DenyPorts,
+		resolver:     net.DefaultResolver,
+		dialer:       &net.Dialer{},
+	}, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	nets := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidRule, "%s: %s", cidr, err.Error())
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// Transport configures base to dial through the filter and returns a RoundTripper which checks
+// every request made with it.
+func (f *Filter) Transport(base *http.Transport) http.RoundTripper {
+	base.DialContext = f.DialContext
+	return &roundTripper{filter: f, next: base}
+}
+
+// CheckURL checks the scheme, port and host of a target URL. Hosts which are IP addresses are also
+// checked against the network rules, so they are enforced when the request is sent via a proxy. hostAllowed
+// is true if the host matched an allow rule, in which case the resolved addresses only need to pass deny rules.
+func (f *Filter) CheckURL(target *url.URL) (hostAllowed bool, err error) {
+	scheme := strings.ToLower(target.Scheme)
+	if !lo.Contains(f.allowSchemes, scheme) {
+		return false, errors.Wrapf(ErrDestinationDenied, "scheme %q is not allowed", scheme)
+	}
+
+	port, err := targetPort(target)
+	if err != nil {
+		return false, err
+	}
+	if lo.Contains(f.denyPorts, port) {
+		return false, errors.Wrapf(ErrDestinationDenied, "port %d is denied", port)
+	}
+	if len(f.allowPorts) > 0 && !lo.Contains(f.allowPorts, port) {
+		return false, errors.Wrapf(ErrDestinationDenied, "port %d is not allowed", port)
+	}
+
+	host := strings.ToLower(target.Hostname())
+	if matchHost(f.denyHosts, host) {
+		return false, errors.Wrapf(ErrDestinationDenied, "host %q is denied", host)
+	}
+
+	hostAllowed = matchHost(f.allowHosts, host)
+	if ip := net.ParseIP(host); ip != nil {
+		if err := f.CheckIP(ip, hostAllowed); err != nil {
+			return false, err
+		}
+	}
+	return hostAllowed, nil
+}
+
+// CheckIP checks a resolved address against the network rules.
+func (f *Filter) CheckIP(ip net.IP, hostAllowed bool) error {
+	for _, denyNet := range f.denyNets {
+		if denyNet.Contains(ip) {
+			return errors.Wrapf(ErrDestinationDenied, "address %s is in denied network %s", ip, denyNet)
+		}
+	}
+
+	if hostAllowed || (len(f.allowHosts) == 0 && len(f.allowNets) == 0) {
+		return nil
+	}
+
+	for _, allowNet := range f.allowNets {
+		if allowNet.Contains(ip) {
+			return nil
+		}
+	}
+	return errors.Wrapf(ErrDestinationDenied, "address %s is not in an allowed network", ip)
+}
+
+// DialContext resolves the address and dials the first resolved IP permitted by the filter.
+// Dialling the checked IP directly prevents the name being re-resolved to a different address.
+func (f *Filter) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	// Connections to a proxy are to an operator configured address, and the proxy resolves the target.
+	if proxied, _ := ctx.Value(proxiedKey).(bool); proxied {
+		return f.dialer.DialContext(ctx, network, address) //nolint:wrapcheck
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "DialContext")
+	}
+
+	addrs, err := f.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errors.Wrap(err, "DialContext")
+	}
+
+	hostAllowed, _ := ctx.Value(hostAllowedKey).(bool)
+
+	lastErr := errors.Wrapf(ErrDestinationDenied, "no addresses for %s", host)
+	for _, addr := range addrs {
+		if err := f.CheckIP(addr.IP, hostAllowed); err != nil {
+			lastErr = err
+			continue
+		}
+		conn, err := f.dialer.DialContext(ctx, network, net.JoinHostPort(addr.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// CheckRedirect checks the redirects of requests which are sent without the filter, such as requests for
+// targets configured by the operator. Redirects keeping the scheme and host of the original request are
+// followed, and redirects anywhere else must pass the filter. The addresses the host resolves to are checked
+// before the redirect is followed, so a redirect to a name can't be used to reach a denied network.
+func (f *Filter) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if strings.EqualFold(req.URL.Scheme, via[0].URL.Scheme) && strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		return nil
+	}
+
+	hostAllowed, err := f.CheckURL(req.URL)
+	if err != nil {
+		return err
+	}
+	host := req.URL.Hostname()
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	addrs, err := f.resolver.LookupIPAddr(req.Context(), host)
+	if err != nil {
+		return errors.Wrap(err, "CheckRedirect")
+	}
+	for _, addr := range addrs {
+		if err := f.CheckIP(addr.IP, hostAllowed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roundTripper checks each request before passing it to the wrapped transport.
+type roundTripper struct {
+	filter *Filter
+	next   *http.Transport
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	hostAllowed, err := rt.filter.CheckURL(req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(req.Context(), hostAllowedKey, hostAllowed)
+	if rt.next.Proxy != nil {
+		if proxyURL, err := rt.next.Proxy(req); err == nil && proxyURL != nil {
+			ctx = context.WithValue(ctx, proxiedKey, true)
+		}
+	}
+
+	return rt.next.RoundTrip(req.WithContext(ctx)) //nolint:wrapcheck
+}
+
+// matchHost returns true if host matches any of the globs.
+func matchHost(globs []string, host string) bool {
+	return lo.ContainsBy(globs, func(glob string) bool {
+		matched, err := path.Match(glob, host)
+		return err == nil && matched
+	})
+}
+
+// targetPort returns the explicit or scheme default port of the URL.
+func targetPort(target *url.URL) (int, error) {
+	if portStr := target.Port(); portStr != "" {
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			return 0, errors.Wrapf(ErrDestinationDenied, "invalid port %q", portStr)
+		}
+		return port, nil
+	}
+
+	switch strings.ToLower(target.Scheme) {
+	case "http":
+		return 80, nil //nolint:gomnd
+	case "https":
+		return 443, nil //nolint:gomnd
+	default:
+		return 0, errors.Wrap(ErrDestinationDenied, fmt.Sprintf("no default port for scheme %q", target.Scheme))
+	}
+}
@@ -0,0 +1,202 @@
+package netfilter
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// defaultDenyCIDRs are the default denied networks of Config.
+//
+//nolint:gochecknoglobals
+var defaultDenyCIDRs = []string{
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+	"192.168.0.0/16", "::/128", "::1/128", "fc00::/7", "fe80::/10",
+}
+
+func newFilter(t *testing.T, config Config) *Filter {
+	t.Helper()
+	if config.AllowSchemes == nil {
+		config.AllowSchemes = []string{"http", "https"}
+	}
+	filter, err := New(config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return filter
+}
+
+func TestCheckURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		config      Config
+		target      string
+		denied      bool
+		hostAllowed bool
+	}{
+		{"public host", Config{DenyCIDRs: defaultDenyCIDRs}, "https://example.com/badge.json", false, false},
+		{"denied scheme", Config{}, "ftp://example.com/badge.json", true, false},
+		{"denied port", Config{DenyPorts: []int{8080}}, "http://example.com:8080/", true, false},
+		{"port not allowed", Config{AllowPorts: []int{443}}, "http://example.com/", true, false},
+		{"denied host", Config{DenyHosts: []string{"*.internal"}}, "http://ci.internal/", true, false},
+		{"allowed host", Config{AllowHosts: []string{"*.example.com"}}, "http://ci.example.com/", false, true},
+		{"metadata IP literal", Config{DenyCIDRs: defaultDenyCIDRs}, "http://169.254.169.254/latest/", true, false},
+		{"loopback IP literal", Config{DenyCIDRs: defaultDenyCIDRs}, "http://127.0.0.1:8080/", true, false},
+		{"IPv6 IP literal", Config{DenyCIDRs: defaultDenyCIDRs}, "http://[::1]/", true, false},
+		{"public IP literal", Config{DenyCIDRs: defaultDenyCIDRs}, "http://93.184.216.34/", false, false},
+		{"IP literal not allowed", Config{AllowCIDRs: []string{"192.0.2.0/24"}}, "http://198.51.100.1/", true, false},
+		{"IP literal allowed", Config{AllowCIDRs: []string{"192.0.2.0/24"}}, "http://192.0.2.10/", false, false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			target, err := url.Parse(test.target)
+			if err != nil {
+				t.Fatalf("url.Parse: %v", err)
+			}
+
+			hostAllowed, err := newFilter(t, test.config).CheckURL(target)
+			if denied := errors.Is(err, ErrDestinationDenied); denied != test.denied {
+				t.Fatalf("CheckURL(%s) error = %v, want denied %v", test.target, err, test.denied)
+			}
+			if hostAllowed != test.hostAllowed {
+				t.Errorf("CheckURL(%s) hostAllowed = %v, want %v", test.target, hostAllowed, test.hostAllowed)
+			}
+		})
+	}
+}
+
+func TestDialContext(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	tests := []struct {
+		name   string
+		config Config
+		ctx    context.Context //nolint:containedctx
+		denied bool
+	}{
+		{"denied network", Config{DenyCIDRs: defaultDenyCIDRs}, context.Background(), true},
+		{"network not allowed", Config{AllowCIDRs: []string{"192.0.2.0/24"}}, context.Background(), true},
+		{"allowed network", Config{AllowCIDRs: []string{"127.0.0.0/8"}}, context.Background(), false},
+		{"deny wins over allowed host", Config{DenyCIDRs: defaultDenyCIDRs},
+			context.WithValue(context.Background(), hostAllowedKey, true), true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			conn, err := newFilter(t, test.config).DialContext(test.ctx, "tcp", listener.Addr().String())
+			if conn != nil {
+				conn.Close()
+			}
+			if denied := errors.Is(err, ErrDestinationDenied); denied != test.denied {
+				t.Fatalf("DialContext error = %v, want denied %v", err, test.denied)
+			}
+			if !test.denied && err != nil {
+				t.Fatalf("DialContext: %v", err)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	// The server stands in for both a target and a proxy, answering every request.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	proxyURL, _ := url.Parse(server.URL)
+
+	tests := []struct {
+		name    string
+		proxied bool
+		target  string
+		denied  bool
+	}{
+		{"denied target", false, server.URL, true},
+		{"proxied host", true, "http://badges.example.com/", false},
+		{"proxied denied IP literal", true, "http://169.254.169.254/latest/", true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			base := &http.Transport{}
+			if test.proxied {
+				base.Proxy = http.ProxyURL(proxyURL)
+			}
+			client := &http.Client{Transport: newFilter(t, Config{DenyCIDRs: defaultDenyCIDRs}).Transport(base)}
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, test.target, nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest: %v", err)
+			}
+
+			resp, err := client.Do(req)
+			if resp != nil {
+				resp.Body.Close()
+			}
+			if denied := errors.Is(err, ErrDestinationDenied); denied != test.denied {
+				t.Fatalf("GET %s error = %v, want denied %v", test.target, err, test.denied)
+			}
+			if !test.denied && err != nil {
+				t.Fatalf("GET %s: %v", test.target, err)
+			}
+		})
+	}
+}
+
+func TestCheckRedirect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		original string
+		redirect string
+		denied   bool
+	}{
+		{"same host", "http://127.0.0.1:8080/a", "http://127.0.0.1:8080/b", false},
+		{"other port", "http://127.0.0.1:8080/a", "http://127.0.0.1:9090/b", true},
+		{"other scheme", "https://127.0.0.1/a", "http://127.0.0.1/b", true},
+		{"denied IP literal", "http://127.0.0.1/a", "http://169.254.169.254/latest/", true},
+		{"denied name", "http://127.0.0.1/a", "http://localhost/b", true},
+		{"public IP literal", "http://127.0.0.1/a", "http://93.184.216.34/b", false},
+		{"denied scheme", "http://127.0.0.1/a", "file:///etc/passwd", true},
+	}
+
+	filter := newFilter(t, Config{DenyCIDRs: defaultDenyCIDRs})
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			original := httptest.NewRequest(http.MethodGet, test.original, nil)
+			redirect := httptest.NewRequest(http.MethodGet, test.redirect, nil)
+			err := filter.CheckRedirect(redirect, []*http.Request{original})
+			if denied := errors.Is(err, ErrDestinationDenied); denied != test.denied {
+				t.Fatalf("CheckRedirect(%s) error = %v, want denied %v", test.redirect, err, test.denied)
+			}
+			if !test.denied && err != nil {
+				t.Fatalf("CheckRedirect(%s): %v", test.redirect, err)
+			}
+		})
+	}
+}
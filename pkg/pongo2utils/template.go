@@ -6,6 +6,12 @@ import (
 	"github.com/samber/lo"
 )
 
+// FromStringUnescaped parses a template which doesn't HTML escape its values, for text which is escaped
+// for its output format elsewhere.
+func FromStringUnescaped(tpl string) (*pongo2.Template, error) {
+	return pongo2.FromString("{% autoescape off %}" + tpl + "{% endautoescape %}") //nolint:wrapcheck
+}
+
 type Template struct {
 	*pongo2.Template
 }
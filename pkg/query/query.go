@@ -0,0 +1,143 @@
+// Package query implements the JSONPath and JMESPath query expressions which can be evaluated
+// against decoded badge target responses.
+package query
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/jmespath/go-jmespath"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidQuery = errors.New("query expression is invalid")
+	ErrQueryFailed  = errors.New("query evaluation failed")
+)
+
+// jsonPathLanguage is JSONPath with the full gval expression language for filter expressions.
+var jsonPathLanguage = gval.Full(jsonpath.Language()) //nolint:gochecknoglobals
+
+// Query is a compiled query expression.
+type Query interface {
+	// Evaluate runs the query against data and returns the result.
+	Evaluate(data interface{}) (interface{}, error)
+}
+
+// Compile compiles a query expression. Expressions starting with "$" are JSONPath, anything
+// else is JMESPath.
+func Compile(expression string) (Query, error) {
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "$") {
+		evaluable, err := jsonPathLanguage.NewEvaluable(expression)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidQuery, "JSONPath: %s", err.Error())
+		}
+		return jsonPathQuery{evaluable}, nil
+	}
+
+	compiled, err := jmespath.Compile(expression)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidQuery, "JMESPath: %s", err.Error())
+	}
+	return jmesPathQuery{compiled}, nil
+}
+
+type jsonPathQuery struct {
+	evaluable gval.Evaluable
+}
+
+func (q jsonPathQuery) Evaluate(data interface{}) (interface{}, error) {
+	result, err := q.evaluable(context.Background(), normalize(data))
+	if err != nil {
+		return nil, errors.Wrapf(ErrQueryFailed, "JSONPath: %s", err.Error())
+	}
+	return wholeNumbers(result), nil
+}
+
+type jmesPathQuery struct {
+	compiled *jmespath.JMESPath
+}
+
+func (q jmesPathQuery) Evaluate(data interface{}) (interface{}, error) {
+	result, err := q.compiled.Search(normalize(data))
+	if err != nil {
+		return nil, errors.Wrapf(ErrQueryFailed, "JMESPath: %s", err.Error())
+	}
+	return wholeNumbers(result), nil
+}
+
+// normalize converts decoded data to the JSON-like types the query languages expect. Numbers
+// become float64 and maps are keyed by strings, since formats other than JSON decode to other
+// types.
+func normalize(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[k] = normalize(v)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[fmt.Sprint(k)] = normalize(v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, v := range value {
+			result[i] = normalize(v)
+		}
+		return result
+	case nil, string, bool, float64:
+		return value
+	}
+
+	reflected := reflect.ValueOf(data)
+	switch reflected.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint())
+	case reflect.Float32:
+		return reflected.Float()
+	default:
+		return data
+	}
+}
+
+// maxExactInteger is the largest integer every smaller integer of which a float64 can represent exactly.
+const maxExactInteger = 1 << 53
+
+// wholeNumbers converts whole numbers in query results to int64, so templates render them without
+// a fractional part. normalize makes every number a float64, so a count or a sum of integers would
+// otherwise render as e.g. 5.000000.
+func wholeNumbers(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			result[k] = wholeNumbers(v)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, v := range value {
+			result[i] = wholeNumbers(v)
+		}
+		return result
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) <= maxExactInteger {
+			return int64(value)
+		}
+		return value
+	default:
+		return data
+	}
+}
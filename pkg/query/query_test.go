@@ -0,0 +1,113 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/pkg/errors"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{
+		"status": "success",
+		"count":  5,
+		"ratio":  0.25,
+		"list": []interface{}{
+			map[string]interface{}{"name": "a", "value": 1.0, "ok": true},
+			map[string]interface{}{"name": "b", "value": 5.0, "ok": false},
+			map[string]interface{}{"name": "c", "value": 2.5, "ok": true},
+		},
+		// YAML decodes maps with interface{} keys and integers as ints.
+		"yaml": map[interface{}]interface{}{"key": uint8(7), 1: "one"},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       interface{}
+		err        error
+	}{
+		{"jsonpath field", "$.status", "success", nil},
+		{"jsonpath nested", "$.list[1].name", "b", nil},
+		{"jsonpath wildcard", "$.list[*].name", []interface{}{"a", "b", "c"}, nil},
+		{"jsonpath filter", `$.list[?(@.ok)].name`, []interface{}{"a", "c"}, nil},
+		{"jsonpath yaml map", "$.yaml.key", int64(7), nil},
+		{"jsonpath whole numbers", "$.list[*].value", []interface{}{int64(1), int64(5), 2.5}, nil},
+		{"jmespath field", "status", "success", nil},
+		{"jmespath projection", "list[?ok].name", []interface{}{"a", "c"}, nil},
+		{"jmespath max", "max(list[*].value)", int64(5), nil},
+		{"jmespath length", "length(list)", int64(3), nil},
+		{"jmespath sum", "sum(list[*].value)", 8.5, nil},
+		{"jmespath int field", "count", int64(5), nil},
+		{"jmespath fraction", "ratio", 0.25, nil},
+		{"jmespath yaml key", `yaml."1"`, "one", nil},
+		{"jmespath object", "list[0]", map[string]interface{}{"name": "a", "value": int64(1), "ok": true}, nil},
+		{"jmespath missing", "missing", nil, nil},
+		{"invalid jsonpath", "$.list[", nil, ErrInvalidQuery},
+		{"invalid jmespath", "list[", nil, ErrInvalidQuery},
+		{"failed jmespath", "max(status)", nil, ErrQueryFailed},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			compiled, err := Compile(test.expression)
+			var result interface{}
+			if err == nil {
+				result, err = compiled.Evaluate(data)
+			}
+			if !errors.Is(err, test.err) {
+				t.Fatalf("error = %v, want %v", err, test.err)
+			}
+			if !reflect.DeepEqual(result, test.want) {
+				t.Errorf("result = %#v, want %#v", result, test.want)
+			}
+		})
+	}
+}
+
+func TestQueryTemplate(t *testing.T) {
+	t.Parallel()
+
+	data := map[string]interface{}{"list": []interface{}{
+		map[string]interface{}{"a": 3.0},
+		map[string]interface{}{"a": 5.0},
+		map[string]interface{}{"a": 0.5},
+	}}
+
+	tests := []struct {
+		expression string
+		want       string
+	}{
+		{"max(list[*].a)", "5"},
+		{"min(list[*].a)", "0.500000"},
+		{"length(list)", "3"},
+		{"$.list[0].a", "3"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+			compiled, err := Compile(test.expression)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			result, err := compiled.Evaluate(data)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			rendered, err := pongo2.RenderTemplateString("{{ q }}", pongo2.Context{"q": result})
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if rendered != test.want {
+				t.Errorf("rendered %q, want %q", rendered, test.want)
+			}
+		})
+	}
+}
@@ -1,23 +1,49 @@
 package badgeconfig
 
 import (
-	"fmt"
+	"io/fs"
 	"io/ioutil"
+	"net/url"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/samber/lo"
+	"github.com/wrouesnel/badgeserv/pkg/dataformat"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
 
 var (
-	ErrConfigLoading = errors.New("error while loading configuration")
+	ErrConfigLoading     = errors.New("error while loading configuration")
+	ErrUnknownBase       = errors.New("extends an unknown badge")
+	ErrInheritanceCycle  = errors.New("badge inheritance cycle")
+	ErrInvalidExtends    = errors.New("extends must be the name of a badge")
+	ErrInvalidBadgeEntry = errors.New("badge definition must be a map")
 )
 
 type BadgeDesc struct {
-	Label   string `mapstructure:"label" help:"Label template"`
+	Label      string `mapstructure:"label" help:"Label template"`
+	Message    string `mapstructure:"message" help:"Message template"`
+	Color      string `mapstructure:"color" help:"Color template"`
+	LabelColor string `mapstructure:"label_color" help:"Label color template"`
+	Style      string `mapstructure:"style" help:"Badge style"`
+
+	ColorRules *ColorRules `mapstructure:"color_rules" help:"Rules choosing the color from the badge value, overriding the color template"`
+	Segments   []Segment   `mapstructure:"segments" help:"Segments following the label, instead of the message and color"`
+
+	Logo      string `mapstructure:"logo" help:"Logo icon name or image data URI"`
+	LogoColor string `mapstructure:"logo_color" help:"Color of SVG logos"`
+	LogoWidth int    `mapstructure:"logo_width" help:"Width of the logo"`
+
+	Font string `mapstructure:"font" help:"Path of a TTF or OTF font for the badge text"`
+}
+
+// Segment is a section of a badge following the label.
+type Segment struct {
 	Message string `mapstructure:"message" help:"Message template"`
 	Color   string `mapstructure:"color" help:"Color template"`
 }
@@ -29,12 +55,30 @@ type BadgeExample struct {
 	Parameters  map[string]string
 }
 
+// Query returns the example parameters encoded as URL query values.
+func (e BadgeExample) Query() url.Values {
+	qry := url.Values{}
+	for k, v := range e.Parameters {
+		qry.Set(k, v)
+	}
+	return qry
+}
+
 type BadgeDefinition struct {
-	BadgeDesc   `mapstructure:",squash"`
-	Target      string            `mapstructure:"target" help:"target URL to resolve badge data from"`
-	Parameters  map[string]string `mapstructure:"parameters" help:"Accepted parameters for the interface"`
-	Examples    []BadgeExample    `mapstructure:"examples" help:"List of example badges to include"`
-	Description string            `mapstructure:"description"`
+	BadgeDesc    `mapstructure:",squash"`
+	Target       string               `mapstructure:"target" help:"target URL to resolve badge data from"`
+	TargetFormat dataformat.Format    `mapstructure:"target_format" help:"Data format of the target response (default auto)"`
+	Query        string               `mapstructure:"query" help:"JSONPath or JMESPath query evaluated against the target response"`
+	Parameters   map[string]Parameter `mapstructure:"parameters" help:"Accepted parameters for the interface"`
+	Examples     []BadgeExample       `mapstructure:"examples" help:"List of example badges to include"`
+	Description  string               `mapstructure:"description"`
+	CacheTTL     *time.Duration       `mapstructure:"cache_ttl" help:"Override the default time to cache target responses"`
+	Headers      map[string]Secret    `mapstructure:"headers" help:"Headers to send with the target request"`
+	BasicAuth    *BasicAuth           `mapstructure:"basic_auth" help:"Basic authentication credentials for the target request"`
+	BearerToken  *Secret              `mapstructure:"bearer_token" help:"Bearer token to send with the target request"`
+
+	Extends  string `mapstructure:"extends" help:"Name of a badge this badge inherits its settings from"`
+	Abstract bool   `mapstructure:"abstract" help:"Badge is only a base for other badges and is not served"`
 }
 
 type Config struct {
@@ -46,8 +90,13 @@ type Config struct {
 func Decoder(target interface{}, allowUnused bool) (*mapstructure.Decoder, error) {
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		ErrorUnused: !allowUnused,
-		DecodeHook:  mapstructure.ComposeDecodeHookFunc(mapstructure.TextUnmarshallerHookFunc()),
-		Result:      target,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.TextUnmarshallerHookFunc(),
+			mapstructure.StringToTimeDurationHookFunc(),
+			stringToSecretHookFunc(),
+			stringToParameterHookFunc(),
+		),
+		Result: target,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "Load: BUG - decoder configuration rejected")
@@ -67,74 +116,276 @@ func loadConfigMap(configBytes []byte) (map[string]interface{}, error) {
 	return configMap, nil
 }
 
-// Load loads a configuration file from the supplied bytes.
-func Load(configData []byte) (*Config, error) {
-	//defaultMap := loadDefaultConfigMap()
+// badgeMaps holds the config maps of predefined badges by name, before inheritance.
+type badgeMaps map[string]map[string]interface{}
+
+// parseConfig parses a configuration file and returns the config maps of its predefined badges.
+func parseConfig(configData []byte) (badgeMaps, error) {
 	configMap, err := loadConfigMap(configData)
 	if err != nil {
-		return nil, errors.Wrap(err, "Load: failed")
+		return nil, errors.Wrap(err, "parseConfig: failed")
 	}
 
-	// Merge default configuration into the configMap
-	//configMapMerge(defaultMap, configMap)
-
 	// Do an initial decode to detect any unused key errors
-	cfg := new(Config)
-	decoder, err := Decoder(cfg, false)
+	decoder, err := Decoder(new(Config), false)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Load: config map decoder failed to initialize")
+		return nil, errors.Wrapf(err, "parseConfig: config map decoder failed to initialize")
 	}
-
 	if err := decoder.Decode(configMap); err != nil {
-		return nil, errors.Wrap(err, "Load: config map decoding failed")
+		return nil, errors.Wrap(err, "parseConfig: config map decoding failed")
+	}
+
+	badges := badgeMaps{}
+	predefined, _ := configMap["predefined_badges"].(map[string]interface{})
+	for name, value := range predefined {
+		badgeMap, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidBadgeEntry, "%q", name)
+		}
+		badges[name] = badgeMap
+	}
+	return badges, nil
+}
+
+// mergeBadgeMaps returns the badge config map base with override applied. The headers, parameters and
+// color rules are merged by key so a badge can add a header to those of its base. Anything else, including
+// the values in those maps and credentials such as bearer_token and basic_auth, is replaced as a whole so
+// secrets from the base and the badge are never mixed.
+func mergeBadgeMaps(base map[string]interface{}, override map[string]interface{}) map[string]interface{} {
+	result := lo.Assign(base)
+	for key, value := range override {
+		switch key {
+		case "headers", "parameters", "color_rules":
+			baseMap, baseIsMap := result[key].(map[string]interface{})
+			overrideMap, overrideIsMap := value.(map[string]interface{})
+			if baseIsMap && overrideIsMap {
+				result[key] = lo.Assign(baseMap, overrideMap)
+				continue
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// resolveInheritance merges the badges each badge extends into it. Badges which can't be resolved are
+// left out and their errors returned.
+func resolveInheritance(badges badgeMaps) (badgeMaps, []error) {
+	resolved := badgeMaps{}
+	failed := map[string]bool{}
+	errs := []error{}
+
+	var resolve func(name string, chain []string) (map[string]interface{}, error)
+	resolve = func(name string, chain []string) (map[string]interface{}, error) {
+		if badgeMap, ok := resolved[name]; ok {
+			return badgeMap, nil
+		}
+		if lo.Contains(chain, name) {
+			return nil, errors.Wrap(ErrInheritanceCycle, strings.Join(append(chain, name), " -> "))
+		}
+
+		badgeMap := badges[name]
+		extends, hasBase := badgeMap["extends"]
+		if !hasBase {
+			resolved[name] = badgeMap
+			return badgeMap, nil
+		}
+
+		baseName, ok := extends.(string)
+		if !ok || baseName == "" {
+			return nil, errors.Wrapf(ErrInvalidExtends, "badge %q", name)
+		}
+		if _, ok := badges[baseName]; !ok {
+			return nil, errors.Wrapf(ErrUnknownBase, "badge %q extends %q", name, baseName)
+		}
+		baseMap, err := resolve(baseName, append(chain, name))
+		if err != nil {
+			return nil, err
+		}
+
+		// Being a base is not inherited.
+		baseMap = lo.OmitByKeys(baseMap, []string{"abstract"})
+		merged := mergeBadgeMaps(baseMap, lo.OmitByKeys(badgeMap, []string{"extends"}))
+		resolved[name] = merged
+		return merged, nil
+	}
+
+	// Badges are resolved in order so errors are reported consistently.
+	names := lo.Keys(badges)
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := resolve(name, nil); err != nil {
+			failed[name] = true
+			errs = append(errs, err)
+		}
+	}
+
+	return lo.OmitBy(resolved, func(name string, _ map[string]interface{}) bool {
+		return failed[name]
+	}), errs
+}
+
+// decodeBadges decodes resolved badge config maps into a Config. Abstract badges are left out. Badges
+// which fail to decode or validate are left out too and their errors returned.
+func decodeBadges(badges badgeMaps) (*Config, []error) {
+	cfg := &Config{PredefinedBadges: map[string]BadgeDefinition{}}
+	errs := []error{}
+	names := lo.Keys(badges)
+	sort.Strings(names)
+	for _, name := range names {
+		badgeDef, err := decodeBadge(badges[name])
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "badge %q", name))
+			continue
+		}
+		if !badgeDef.Abstract {
+			cfg.PredefinedBadges[name] = badgeDef
+		}
 	}
+	return cfg, errs
+}
 
+// decodeBadge decodes and validates a single resolved badge config map.
+func decodeBadge(badgeMap map[string]interface{}) (BadgeDefinition, error) {
 	// Do the decode after inheritance and allow unused key errors.
-	cfg = new(Config)
-	decoder, err = Decoder(cfg, true)
+	badgeDef := BadgeDefinition{}
+	decoder, err := Decoder(&badgeDef, true)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Load: second-pass config map decoder failed to initialize")
+		return badgeDef, errors.Wrapf(err, "decodeBadge: decoder failed to initialize")
+	}
+	if err := decoder.Decode(badgeMap); err != nil {
+		return badgeDef, errors.Wrapf(err, "decodeBadge: decoding failed")
+	}
+	badgeDef.resolveEscapes()
+	for paramName, parameter := range badgeDef.Parameters {
+		if err := parameter.Validate(); err != nil {
+			return badgeDef, errors.Wrapf(err, "decodeBadge: parameter %q", paramName)
+		}
+	}
+	if !badgeDef.Abstract {
+		if err := badgeDef.ValidateCredentials(); err != nil {
+			return badgeDef, errors.Wrapf(err, "decodeBadge: credentials")
+		}
 	}
+	return badgeDef, nil
+}
 
-	if err := decoder.Decode(configMap); err != nil {
-		return nil, errors.Wrap(err, "Load: second-pass config map decoding failed")
+// configErrors is ErrConfigLoading with the errors which caused it. errors.Is matches ErrConfigLoading
+// and each of the causes.
+type configErrors []error
+
+func (e configErrors) Error() string {
+	return ErrConfigLoading.Error() + ": " + strings.Join(lo.Map(e, func(err error, _ int) string {
+		return err.Error()
+	}), "; ")
+}
+
+func (e configErrors) Is(target error) bool {
+	return target == ErrConfigLoading || lo.SomeBy(e, func(err error) bool {
+		return errors.Is(err, target)
+	})
+}
+
+// Load loads a configuration file from the supplied bytes. If some badges fail to load, the rest are
+// returned with an error describing the failures.
+func Load(configData []byte) (*Config, error) {
+	badges, err := parseConfig(configData)
+	if err != nil {
+		return nil, errors.Wrap(err, "Load: failed")
+	}
+
+	resolved, errs := resolveInheritance(badges)
+	config, decodeErrs := decodeBadges(resolved)
+	errs = append(errs, decodeErrs...)
+	if len(errs) > 0 {
+		return config, configErrors(errs)
+	}
+	return config, nil
+}
+
+// ScanDir returns the directories under dirPath and the configuration files in them which LoadDir
+// reads. Hidden files and directories are skipped, such as the ..data directories of Kubernetes
+// ConfigMap volumes which would otherwise load every file twice.
+func ScanDir(dirPath string) ([]string, []string, error) {
+	dirs := []string{}
+	files := []string{}
+	err := filepath.WalkDir(dirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dirPath && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+		if ext := filepath.Ext(path); ext == ".yml" || ext == ".yaml" {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "ScanDir")
 	}
-	return cfg, nil
+	return dirs, files, nil
 }
 
-// LoadDir loads a directory of predefined badge configuration files.
+// LoadDir loads a directory of predefined badge configuration files, including subdirectories. Badges
+// can extend badges defined in any file. If some files or badges fail to load, the rest are returned with
+// an error describing the failures.
 func LoadDir(dirPath string) (*Config, error) {
-	// Note: ick.
 	logger := zap.L()
 
-	matches := lo.FlatMap([]string{"yml", "yaml"}, func(ext string, _ int) []string {
-		extMatches, _ := filepath.Glob(fmt.Sprintf("%s/*.yml", dirPath))
-		return extMatches
-	})
+	_, configPaths, err := ScanDir(dirPath)
+	if err != nil {
+		return &Config{PredefinedBadges: map[string]BadgeDefinition{}}, errors.Wrap(err, "LoadDir")
+	}
 
-	finalConfig := Config{PredefinedBadges: map[string]BadgeDefinition{}}
+	badges := badgeMaps{}
+	badgePaths := map[string]string{}
 
-	errors := []error{}
-	for _, configPath := range matches {
+	errs := []error{}
+	for _, configPath := range configPaths {
 		logger.Debug("Loading predefined badges from config file", zap.String("config_path", configPath))
 		configBytes, err := ioutil.ReadFile(configPath)
 		if err != nil {
 			logger.Warn("Could not read config file", zap.String("config_path", configPath), zap.Error(err))
-			errors = append(errors, err)
+			errs = append(errs, err)
 			continue
 		}
-		config, err := Load(configBytes)
+		fileBadges, err := parseConfig(configBytes)
 		if err != nil {
 			logger.Warn("Config parsing error", zap.String("config_path", configPath), zap.Error(err))
-			errors = append(errors, err)
+			errs = append(errs, errors.Wrap(err, configPath))
 			continue
 		}
-		finalConfig.PredefinedBadges = lo.Assign(finalConfig.PredefinedBadges, config.PredefinedBadges)
+		for name, badgeMap := range fileBadges {
+			if otherPath, found := badgePaths[name]; found {
+				logger.Warn("Predefined badge is defined more than once, the later definition is used",
+					zap.String("badge", name), zap.String("config_path", configPath),
+					zap.String("overridden_path", otherPath))
+			}
+			badges[name] = badgeMap
+			badgePaths[name] = configPath
+		}
+	}
+
+	resolved, inheritErrs := resolveInheritance(badges)
+	errs = append(errs, inheritErrs...)
+
+	finalConfig, decodeErrs := decodeBadges(resolved)
+	errs = append(errs, decodeErrs...)
+	for _, err := range append(inheritErrs, decodeErrs...) {
+		logger.Warn("Predefined badge could not be loaded", zap.Error(err))
 	}
 
-	if len(errors) > 0 {
-		return &finalConfig, ErrConfigLoading
+	if len(errs) > 0 {
+		return finalConfig, configErrors(errs)
 	}
 
-	return &finalConfig, nil
+	return finalConfig, nil
 }
@@ -0,0 +1,164 @@
+package badgeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestLoadInheritance(t *testing.T) {
+	t.Parallel()
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretFile, []byte("file-token"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	base := `
+  base:
+    abstract: true
+    target: https://example.com
+    label: base
+    headers:
+      Accept: application/json
+      PRIVATE-TOKEN: {env: BADGESERV_TEST_UNSET_SECRET}
+    bearer_token: {env: BADGESERV_TEST_UNSET_SECRET}
+`
+
+	tests := []struct {
+		name    string
+		badge   string
+		label   string
+		headers map[string]string
+		err     error
+	}{
+		{
+			name:  "headers merged by key",
+			badge: "{extends: base, bearer_token: token, headers: {PRIVATE-TOKEN: token, X-Extra: extra}}",
+			label: "base",
+			headers: map[string]string{"Accept": "application/json", "Authorization": "Bearer token",
+				"Private-Token": "token", "X-Extra": "extra"},
+		},
+		{
+			name: "bearer token replaced as a whole",
+			badge: "{extends: base, label: badge, headers: {PRIVATE-TOKEN: token}, bearer_token: {file: " +
+				secretFile + "}}",
+			label: "badge",
+			headers: map[string]string{"Accept": "application/json", "Authorization": "Bearer file-token",
+				"Private-Token": "token"},
+		},
+		{
+			name:  "header value replaced as a whole",
+			badge: "{extends: base, bearer_token: token, headers: {PRIVATE-TOKEN: {file: " + secretFile + "}}}",
+			label: "base",
+			headers: map[string]string{"Authorization": "Bearer token", "Private-Token": "file-token",
+				"Accept": "application/json"},
+		},
+		{
+			name:  "ambiguous header secret override",
+			badge: "{extends: base, bearer_token: token, headers: {PRIVATE-TOKEN: {value: token, env: HOME}}}",
+			err:   ErrSecretInvalid,
+		},
+		{
+			name:  "unknown base",
+			badge: "{extends: missing}",
+			err:   ErrUnknownBase,
+		},
+		{
+			name:  "cycle",
+			badge: "{extends: badge}",
+			err:   ErrInheritanceCycle,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			config, err := Load([]byte("predefined_badges:" + base + "  badge: " + test.badge + "\n"))
+			if test.err != nil {
+				if !errors.Is(err, test.err) || !errors.Is(err, ErrConfigLoading) {
+					t.Fatalf("Load error = %v, want %v", err, test.err)
+				}
+				if _, found := config.PredefinedBadges["badge"]; found {
+					t.Errorf("badge with errors was loaded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if _, found := config.PredefinedBadges["base"]; found {
+				t.Errorf("abstract base badge was loaded")
+			}
+			badge := config.PredefinedBadges["badge"]
+			if badge.Label != test.label {
+				t.Errorf("Label = %q, want %q", badge.Label, test.label)
+			}
+			headers, err := badge.RequestHeaders()
+			if err != nil {
+				t.Fatalf("RequestHeaders: %v", err)
+			}
+			if len(headers) != len(test.headers) {
+				t.Errorf("RequestHeaders = %v, want %v", headers, test.headers)
+			}
+			for name, want := range test.headers {
+				if got := headers.Get(name); got != want {
+					t.Errorf("header %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yml": `
+predefined_badges:
+  shared: {target: "https://example.com/a", label: a}
+  invalid: {target: "https://example.com", parameters: {p: {type: float}}}
+`,
+		"b.yml": `
+predefined_badges:
+  shared: {target: "https://example.com/b", label: b}
+  other: {extends: shared, label: other}
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+	}
+
+	config, err := LoadDir(dir)
+	if !errors.Is(err, ErrConfigLoading) || !errors.Is(err, ErrUnknownParamType) {
+		t.Errorf("LoadDir error = %v, want %v", err, ErrUnknownParamType)
+	}
+	if _, found := config.PredefinedBadges["invalid"]; found {
+		t.Errorf("badge with errors was loaded")
+	}
+
+	tests := []struct {
+		badge  string
+		label  string
+		target string
+	}{
+		{"shared", "b", "https://example.com/b"},
+		{"other", "other", "https://example.com/b"},
+	}
+	for _, test := range tests {
+		badge, found := config.PredefinedBadges[test.badge]
+		if !found {
+			t.Errorf("badge %q was not loaded", test.badge)
+			continue
+		}
+		if badge.Label != test.label || badge.Target != test.target {
+			t.Errorf("badge %q = %q %q, want %q %q", test.badge, badge.Label, badge.Target, test.label, test.target)
+		}
+	}
+}
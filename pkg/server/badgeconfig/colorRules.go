@@ -0,0 +1,149 @@
+package badgeconfig
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Regexp is a regular expression compiled when the configuration is loaded.
+type Regexp struct {
+	*regexp.Regexp
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for regular expressions.
+func (r *Regexp) UnmarshalText(text []byte) error {
+	compiled, err := regexp.Compile(string(text))
+	if err != nil {
+		return errors.Wrap(err, "Regexp.UnmarshalText failed")
+	}
+	r.Regexp = compiled
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for regular expressions.
+func (r *Regexp) MarshalText() ([]byte, error) {
+	if r.Regexp != nil {
+		return []byte(r.String()), nil
+	}
+	return []byte(""), nil
+}
+
+// ColorRules choose the color of a badge from its value, as an alternative to writing conditions in
+// the color template. Rules are checked in order and the first matching rule sets the color. If no
+// rule matches, numeric values are colored by the gradient and anything else by the default.
+type ColorRules struct {
+	Value    string         `mapstructure:"value" help:"Template of the value the rules are checked against (default is the message)"`
+	Rules    []ColorRule    `mapstructure:"rules" help:"Ordered list of rules"`
+	Gradient *ColorGradient `mapstructure:"gradient" help:"Range numeric values are colored from red to green over"`
+	Default  string         `mapstructure:"default" help:"Color used if nothing else matches"`
+}
+
+// ColorRule sets the color of a badge if the value satisfies all of its conditions. Numeric
+// conditions only match values which are numbers, optionally followed by '%'. A rule without
+// conditions always matches.
+type ColorRule struct {
+	GTE    *float64 `mapstructure:"gte" help:"Value is greater than or equal to"`
+	GT     *float64 `mapstructure:"gt" help:"Value is greater than"`
+	LTE    *float64 `mapstructure:"lte" help:"Value is less than or equal to"`
+	LT     *float64 `mapstructure:"lt" help:"Value is less than"`
+	Equals *string  `mapstructure:"equals" help:"Value is equal to"`
+	Match  Regexp   `mapstructure:"match" help:"Value matches the regular expression"`
+	Color  string   `mapstructure:"color" help:"Color of the badge"`
+}
+
+// ColorGradient colors numeric values from red at Min, through yellow, to green at Max.
+type ColorGradient struct {
+	Min     float64 `mapstructure:"min" help:"Value colored red"`
+	Max     float64 `mapstructure:"max" help:"Value colored green"`
+	Reverse bool    `mapstructure:"reverse" help:"Color Min green and Max red instead"`
+}
+
+//nolint:gochecknoglobals,gomnd
+var gradientStops = [][3]float64{
+	{0xe0, 0x5d, 0x44},
+	{0xdf, 0xb3, 0x17},
+	{0x44, 0xcc, 0x11},
+}
+
+// Color returns the color for value. ok is false if no rule matches and there is no default.
+func (c *ColorRules) Color(value string) (color string, ok bool) {
+	number, isNumber := parseNumber(value)
+
+	for _, rule := range c.Rules {
+		if rule.matches(value, number, isNumber) {
+			return rule.Color, true
+		}
+	}
+
+	if c.Gradient != nil && isNumber {
+		return c.Gradient.Color(number), true
+	}
+
+	if c.Default != "" {
+		return c.Default, true
+	}
+
+	return "", false
+}
+
+// matches returns true if the value satisfies all the conditions of the rule.
+func (r ColorRule) matches(value string, number float64, isNumber bool) bool {
+	numeric := []struct {
+		limit *float64
+		cmp   func(float64, float64) bool
+	}{
+		{r.GTE, func(n, limit float64) bool { return n >= limit }},
+		{r.GT, func(n, limit float64) bool { return n > limit }},
+		{r.LTE, func(n, limit float64) bool { return n <= limit }},
+		{r.LT, func(n, limit float64) bool { return n < limit }},
+	}
+	for _, condition := range numeric {
+		if condition.limit != nil && (!isNumber || !condition.cmp(number, *condition.limit)) {
+			return false
+		}
+	}
+
+	if r.Equals != nil && *r.Equals != value {
+		return false
+	}
+	if r.Match.Regexp != nil && !r.Match.MatchString(value) {
+		return false
+	}
+	return true
+}
+
+// Color returns the hex color of a number in the gradient.
+func (g *ColorGradient) Color(number float64) string {
+	position := 1.0
+	if g.Max != g.Min {
+		position = math.Max(0, math.Min(1, (number-g.Min)/(g.Max-g.Min)))
+	} else if number < g.Min {
+		position = 0
+	}
+	if g.Reverse {
+		position = 1 - position
+	}
+
+	// Interpolate between the two stops either side of the position.
+	scaled := position * float64(len(gradientStops)-1)
+	idx := int(math.Min(scaled, float64(len(gradientStops)-2))) //nolint:gomnd
+	t := scaled - float64(idx)
+	channel := func(c int) uint8 {
+		return uint8(math.Round(gradientStops[idx][c] + t*(gradientStops[idx+1][c]-gradientStops[idx][c])))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", channel(0), channel(1), channel(2))
+}
+
+// parseNumber parses a value as a number, allowing a trailing '%'.
+func parseNumber(value string) (float64, bool) {
+	number, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,78 @@
+package badgeconfig
+
+import "testing"
+
+func TestColorRules(t *testing.T) {
+	t.Parallel()
+
+	config, err := Load([]byte(`
+predefined_badges:
+  rules:
+    target: https://example.com
+    color_rules:
+      rules:
+        - equals: passing
+          color: green
+        - match: "^fail"
+          color: red
+        - gte: 90
+          color: brightgreen
+        - gt: 50
+          lt: 60
+          color: orange
+      default: grey
+  gradient:
+    target: https://example.com
+    color_rules:
+      rules:
+        - lt: 0
+          color: black
+      gradient:
+        min: 0
+        max: 100
+  reversed:
+    target: https://example.com
+    color_rules:
+      gradient:
+        min: 0
+        max: 10
+        reverse: true
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		badge string
+		value string
+		color string
+		ok    bool
+	}{
+		{"rules", "passing", "green", true},
+		{"rules", "failed", "red", true},
+		{"rules", "95%", "brightgreen", true},
+		{"rules", " 90 ", "brightgreen", true},
+		{"rules", "55", "orange", true},
+		{"rules", "60", "grey", true},
+		{"rules", "unknown", "grey", true},
+		{"gradient", "-1", "black", true},
+		{"gradient", "0", "#e05d44", true},
+		{"gradient", "50%", "#dfb317", true},
+		{"gradient", "100", "#44cc11", true},
+		{"gradient", "150", "#44cc11", true},
+		{"gradient", "n/a", "", false},
+		{"reversed", "0", "#44cc11", true},
+		{"reversed", "10", "#e05d44", true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.badge+"/"+test.value, func(t *testing.T) {
+			t.Parallel()
+			color, ok := config.PredefinedBadges[test.badge].ColorRules.Color(test.value)
+			if color != test.color || ok != test.ok {
+				t.Errorf("Color(%q) = %q, %v, want %q, %v", test.value, color, ok, test.color, test.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package badgeconfig
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrInvalidParameter   = errors.New("invalid parameter definition")
+	ErrParameterRequired  = errors.New("parameter is required")
+	ErrParameterInvalid   = errors.New("parameter value is invalid")
+	ErrUnknownParamType   = errors.New("unknown parameter type")
+	ErrUnknownParamEscape = errors.New("unknown parameter escape")
+)
+
+// ParameterType is the type of the values a parameter accepts.
+type ParameterType string
+
+const (
+	ParameterString ParameterType = "string"
+	ParameterInt    ParameterType = "int"
+	// ParameterEnum accepts one of the values listed by the parameter.
+	ParameterEnum ParameterType = "enum"
+	ParameterBool ParameterType = "bool"
+)
+
+// ParameterEscape is how a parameter value is escaped before it is substituted into the target template. If it
+// isn't set, it is derived from where the parameter is used in the target.
+type ParameterEscape string
+
+const (
+	// EscapeQuery escapes values for use anywhere in a URL, including query strings. It is the default for
+	// parameters used in the query string or fragment of the target.
+	EscapeQuery ParameterEscape = "query"
+	// EscapePath escapes values for use as a path segment. It is the default for other parameters.
+	EscapePath ParameterEscape = "path"
+	// EscapeNone substitutes values unchanged, e.g. to let a parameter set several path segments. Values can
+	// then change any part of the target URL, so it must be set explicitly.
+	EscapeNone ParameterEscape = "none"
+)
+
+// templateTagRegex matches the variables and tags of a pongo2 template.
+var templateTagRegex = regexp.MustCompile(`\{\{.*?\}\}|\{%.*?%\}`) //nolint:gochecknoglobals
+
+// Parameter is a query parameter accepted by a predefined badge. A plain string in the configuration is
+// the description of a string parameter.
+type Parameter struct {
+	Description string          `mapstructure:"description" help:"Description of the parameter"`
+	Type        ParameterType   `mapstructure:"type" help:"Type of the parameter: string, int, enum or bool (default string)"`
+	Required    bool            `mapstructure:"required" help:"Requests must set the parameter"`
+	Default     interface{}     `mapstructure:"default" help:"Value used if the request doesn't set the parameter"`
+	Values      []string        `mapstructure:"values" help:"Values accepted by enum parameters"`
+	Pattern     Regexp          `mapstructure:"pattern" help:"Regular expression values must match"`
+	Escape      ParameterEscape `mapstructure:"escape" help:"How values are escaped in the target: query, path or none (default query in the query string, else path)"`
+}
+
+// stringToParameterHookFunc decodes a plain string as the description of a string parameter.
+func stringToParameterHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(Parameter{}) {
+			return data, nil
+		}
+		return Parameter{Description: data.(string)}, nil //nolint:forcetypeassert
+	}
+}
+
+// DefaultValue returns the default value of the parameter as a string.
+func (p Parameter) DefaultValue() (string, bool) {
+	if p.Default == nil {
+		return "", false
+	}
+	return fmt.Sprint(p.Default), true
+}
+
+// Validate checks the parameter definition, including that its default is a valid value.
+func (p Parameter) Validate() error {
+	switch p.Type {
+	case "", ParameterString, ParameterInt, ParameterBool:
+	case ParameterEnum:
+		if len(p.Values) == 0 {
+			return errors.Wrap(ErrInvalidParameter, "enum parameters must list their values")
+		}
+	default:
+		return errors.Wrapf(ErrUnknownParamType, "%q", p.Type)
+	}
+	if !lo.Contains([]ParameterEscape{"", EscapeQuery, EscapePath, EscapeNone}, p.Escape) {
+		return errors.Wrapf(ErrUnknownParamEscape, "%q", p.Escape)
+	}
+
+	if p.Default == nil {
+		return nil
+	}
+	if p.Required {
+		return errors.Wrap(ErrInvalidParameter, "required parameters can't have a default")
+	}
+	switch p.Default.(type) {
+	case string, bool, int, int64, float64:
+	default:
+		return errors.Wrap(ErrInvalidParameter, "default must be a single value")
+	}
+	defaultValue, _ := p.DefaultValue()
+	if _, err := p.Value(defaultValue); err != nil {
+		return errors.Wrapf(ErrInvalidParameter, "default: %s", err.Error())
+	}
+	return nil
+}
+
+// Value checks a value of the parameter and returns it as it is substituted into the target template. Ints
+// are converted to numbers, bools are written as true or false, and anything else is escaped. Escaped values
+// can't be "." or "..", which would change the path of the target. Values are marked safe so the template
+// doesn't HTML escape them as well.
+func (p Parameter) Value(value string) (interface{}, error) {
+	if p.Pattern.Regexp != nil && !p.Pattern.MatchString(value) {
+		return nil, errors.Wrapf(ErrParameterInvalid, "must match %s", p.Pattern.String())
+	}
+
+	switch p.Type {
+	case ParameterInt:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(ErrParameterInvalid, "must be an integer")
+		}
+		return number, nil
+	case ParameterBool:
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.Wrap(ErrParameterInvalid, "must be true or false")
+		}
+		return strconv.FormatBool(flag), nil
+	case ParameterEnum:
+		if !lo.Contains(p.Values, value) {
+			return nil, errors.Wrapf(ErrParameterInvalid, "must be one of %s", strings.Join(p.Values, ", "))
+		}
+	case "", ParameterString:
+	}
+
+	if p.Escape == EscapeNone {
+		return pongo2.AsSafeValue(value), nil
+	}
+	if value == "." || value == ".." {
+		return nil, errors.Wrap(ErrParameterInvalid, "must not be . or ..")
+	}
+	if p.Escape == EscapeQuery {
+		return pongo2.AsSafeValue(url.QueryEscape(value)), nil
+	}
+	return pongo2.AsSafeValue(url.PathEscape(value)), nil
+}
+
+// targetEscape returns the escape for a parameter from where the target template uses it. Parameters used
+// in the query string or fragment are query escaped, and anything else is path escaped.
+func targetEscape(target string, name string) ParameterEscape {
+	nameRegex := regexp.MustCompile(`(^|[^\w.])` + regexp.QuoteMeta(name) + `($|\W)`)
+
+	inQuery := false
+	literalStart := 0
+	for _, tag := range templateTagRegex.FindAllStringIndex(target, -1) {
+		// Only the literal text of the template starts the query string, not text inside tags.
+		inQuery = inQuery || strings.ContainsAny(target[literalStart:tag[0]], "?#")
+		literalStart = tag[1]
+		if inQuery && nameRegex.MatchString(target[tag[0]:tag[1]]) {
+			return EscapeQuery
+		}
+	}
+	return EscapePath
+}
+
+// resolveEscapes sets the escape of parameters which don't set one from where the target uses them.
+func (b *BadgeDefinition) resolveEscapes() {
+	for name, parameter := range b.Parameters {
+		if parameter.Escape == "" {
+			parameter.Escape = targetEscape(b.Target, name)
+			b.Parameters[name] = parameter
+		}
+	}
+}
+
+// TargetParameters returns the values of the badge parameters in query, with defaults applied, for the target
+// template. Query values which aren't parameters are ignored, and empty values are treated as not set. Every
+// invalid or missing parameter is returned as an error.
+func (b BadgeDefinition) TargetParameters(query url.Values) (map[string]interface{}, []error) {
+	names := lo.Keys(b.Parameters)
+	sort.Strings(names)
+
+	values := map[string]interface{}{}
+	errs := []error{}
+	for _, name := range names {
+		parameter := b.Parameters[name]
+
+		value := query.Get(name)
+		if value == "" {
+			defaultValue, hasDefault := parameter.DefaultValue()
+			switch {
+			case hasDefault:
+				value = defaultValue
+			case parameter.Required:
+				errs = append(errs, errors.Wrapf(ErrParameterRequired, "%q", name))
+				continue
+			default:
+				continue
+			}
+		}
+
+		result, err := parameter.Value(value)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "%q", name))
+			continue
+		}
+		values[name] = result
+	}
+	return values, errs
+}
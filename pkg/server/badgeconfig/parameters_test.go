@@ -0,0 +1,210 @@
+package badgeconfig
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/pkg/errors"
+)
+
+func TestTargetParameters(t *testing.T) {
+	t.Parallel()
+
+	config, err := Load([]byte(`
+predefined_badges:
+  badge:
+    target: "https://example.com/{{ path }}/{{ raw }}?q={{ query }}&n={{ count }}&k={{ kind }}&f={{ flag }}"
+    parameters:
+      path:
+        escape: path
+      query:
+        escape: query
+      raw:
+        description: Substituted unchanged
+        escape: none
+      count:
+        type: int
+        default: 5
+      kind:
+        type: enum
+        values: [a, b]
+      flag:
+        type: bool
+      id:
+        required: true
+        pattern: "^[0-9]+$"
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	badge := config.PredefinedBadges["badge"]
+	target, err := pongo2.FromString(badge.Target)
+	if err != nil {
+		t.Fatalf("pongo2.FromString: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		target string
+		errs   []error
+	}{
+		{
+			name:   "escaping",
+			query:  "id=1&path=a%26b/c&query=x%26y%20z&raw=p/q%26r",
+			target: "https://example.com/a&b%2Fc/p/q&r?q=x%26y+z&n=5&k=&f=",
+		},
+		{
+			name:   "typed values",
+			query:  "id=1&count=10&kind=b&flag=1",
+			target: "https://example.com//?q=&n=10&k=b&f=true",
+		},
+		{
+			name:  "missing required",
+			query: "count=",
+			errs:  []error{ErrParameterRequired},
+		},
+		{
+			name:  "invalid values",
+			query: "id=x&count=ten&kind=c&flag=maybe",
+			errs:  []error{ErrParameterInvalid, ErrParameterInvalid, ErrParameterInvalid, ErrParameterInvalid},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			query, err := url.ParseQuery(test.query)
+			if err != nil {
+				t.Fatalf("url.ParseQuery: %v", err)
+			}
+
+			values, errs := badge.TargetParameters(query)
+			if len(errs) != len(test.errs) {
+				t.Fatalf("TargetParameters errors = %v, want %v", errs, test.errs)
+			}
+			for i, err := range errs {
+				if !errors.Is(err, test.errs[i]) {
+					t.Errorf("TargetParameters error %d = %v, want %v", i, err, test.errs[i])
+				}
+			}
+			if len(test.errs) > 0 {
+				return
+			}
+
+			result, err := target.Execute(values)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if result != test.target {
+				t.Errorf("target = %s, want %s", result, test.target)
+			}
+		})
+	}
+}
+
+func TestTargetParametersDefaultEscape(t *testing.T) {
+	t.Parallel()
+
+	config, err := Load([]byte(`
+predefined_badges:
+  badge:
+    target: "https://example.com/api/{{ project }}/pipelines{% if ref %}?ref={{ ref }}{% endif %}#{{ fragment }}"
+    parameters:
+      project: Project path
+      ref: Branch
+      fragment: Fragment
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	badge := config.PredefinedBadges["badge"]
+	target, err := pongo2.FromString(badge.Target)
+	if err != nil {
+		t.Fatalf("pongo2.FromString: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		err   error
+	}{
+		{"plain", "main", nil},
+		{"slash", "group/sub", nil},
+		{"traversal", "../../admin?x=", nil},
+		{"query", "a?b=c&d", nil},
+		{"fragment", "x#", nil},
+		{"encoded", "%2F%2E%2E", nil},
+		{"dot", ".", ErrParameterInvalid},
+		{"dot dot", "..", ErrParameterInvalid},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			query := url.Values{"project": {test.value}, "ref": {test.value}, "fragment": {test.value}}
+			values, errs := badge.TargetParameters(query)
+			if test.err != nil {
+				if len(errs) != len(query) || !errors.Is(errs[0], test.err) {
+					t.Fatalf("TargetParameters errors = %v, want %v", errs, test.err)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("TargetParameters: %v", errs)
+			}
+
+			result, err := target.Execute(values)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			parsed, err := url.Parse(result)
+			if err != nil {
+				t.Fatalf("url.Parse(%s): %v", result, err)
+			}
+
+			// Every value must stay within its own path segment, query value or fragment.
+			want := "/api/" + test.value + "/pipelines"
+			if parsed.Path != want || parsed.EscapedPath() != "/api/"+url.PathEscape(test.value)+"/pipelines" {
+				t.Errorf("target %s path = %q, want %q", result, parsed.Path, want)
+			}
+			if got := parsed.Query(); len(got) != 1 || got.Get("ref") != test.value {
+				t.Errorf("target %s query = %v, want ref=%q", result, got, test.value)
+			}
+			if fragment, _ := url.QueryUnescape(parsed.EscapedFragment()); fragment != test.value {
+				t.Errorf("target %s fragment = %q, want %q", result, fragment, test.value)
+			}
+		})
+	}
+}
+
+func TestParameterValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		parameter Parameter
+		err       error
+	}{
+		{"string", Parameter{}, nil},
+		{"unknown type", Parameter{Type: "float"}, ErrUnknownParamType},
+		{"unknown escape", Parameter{Escape: "html"}, ErrUnknownParamEscape},
+		{"enum without values", Parameter{Type: ParameterEnum}, ErrInvalidParameter},
+		{"invalid default", Parameter{Type: ParameterInt, Default: "x"}, ErrInvalidParameter},
+		{"required with default", Parameter{Required: true, Default: "x"}, ErrInvalidParameter},
+		{"int default", Parameter{Type: ParameterInt, Default: 3}, nil},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			if err := test.parameter.Validate(); !errors.Is(err, test.err) {
+				t.Errorf("Validate = %v, want %v", err, test.err)
+			}
+		})
+	}
+}
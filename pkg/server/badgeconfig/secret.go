@@ -0,0 +1,174 @@
+package badgeconfig
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"go.withmatt.com/httpheaders"
+)
+
+var (
+	ErrSecretNotSet      = errors.New("secret environment variable is not set")
+	ErrSecretInvalid     = errors.New("secret may only set one of value, env or file")
+	ErrBasicAuthUsername = errors.New("basic_auth requires a username")
+	ErrConflictingAuth   = errors.New("only one of basic_auth and bearer_token may be set")
+)
+
+// Secret is a value used when requesting a badge target which should not be written into
+// the configuration file. It is read from an environment variable or a file when the request
+// is made, so rotated secrets are picked up without reloading. A plain string in the
+// configuration is used as a literal value.
+type Secret struct {
+	Value string `mapstructure:"value" help:"Literal value"`
+	Env   string `mapstructure:"env" help:"Environment variable to read the value from"`
+	File  string `mapstructure:"file" help:"File to read the value from. Surrounding whitespace is trimmed"`
+}
+
+// Validate checks that the secret sets at most one of its sources. It doesn't read the secret.
+func (s Secret) Validate() error {
+	set := 0
+	for _, source := range []string{s.Value, s.Env, s.File} {
+		if source != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return ErrSecretInvalid
+	}
+	return nil
+}
+
+// Resolve returns the value of the secret.
+func (s Secret) Resolve() (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	switch {
+	case s.Env != "":
+		value, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", errors.Wrap(ErrSecretNotSet, s.Env)
+		}
+		return value, nil
+	case s.File != "":
+		value, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", errors.Wrap(err, "Secret.Resolve")
+		}
+		return strings.TrimSpace(string(value)), nil
+	default:
+		return s.Value, nil
+	}
+}
+
+// String never includes the secret value so secrets can't leak into logs.
+func (s Secret) String() string {
+	switch {
+	case s.Env != "":
+		return fmt.Sprintf("env:%s", s.Env)
+	case s.File != "":
+		return fmt.Sprintf("file:%s", s.File)
+	default:
+		return "<redacted>"
+	}
+}
+
+// BasicAuth configures HTTP basic authentication for a badge target.
+type BasicAuth struct {
+	Username Secret `mapstructure:"username"`
+	Password Secret `mapstructure:"password"`
+}
+
+// RequestHeaders resolves the headers and credentials configured for a badge definition into the
+// headers sent with the target request.
+func (d BadgeDefinition) RequestHeaders() (http.Header, error) {
+	if d.BasicAuth != nil && d.BearerToken != nil {
+		return nil, ErrConflictingAuth
+	}
+
+	headers := http.Header{}
+	for name, secret := range d.Headers {
+		value, err := secret.Resolve()
+		if err != nil {
+			return nil, errors.Wrapf(err, "header %s", name)
+		}
+		headers.Set(name, value)
+	}
+
+	if d.BasicAuth != nil {
+		username, err := d.BasicAuth.Username.Resolve()
+		if err != nil {
+			return nil, errors.Wrap(err, "basic_auth username")
+		}
+		if username == "" {
+			return nil, ErrBasicAuthUsername
+		}
+		password, err := d.BasicAuth.Password.Resolve()
+		if err != nil {
+			return nil, errors.Wrap(err, "basic_auth password")
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		headers.Set(httpheaders.Authorization, "Basic "+credentials)
+	}
+
+	if d.BearerToken != nil {
+		token, err := d.BearerToken.Resolve()
+		if err != nil {
+			return nil, errors.Wrap(err, "bearer_token")
+		}
+		headers.Set(httpheaders.Authorization, "Bearer "+token)
+	}
+
+	return headers, nil
+}
+
+// ValidateCredentials checks that the credentials of the badge definition don't conflict and that its secrets
+// are well formed, so a bad configuration is found when it is loaded rather than by the first request. Secrets
+// aren't read, so secrets which are only available later, or are rotated, don't fail the load.
+func (d BadgeDefinition) ValidateCredentials() error {
+	if d.BasicAuth != nil && d.BearerToken != nil {
+		return ErrConflictingAuth
+	}
+
+	for name, secret := range d.Headers {
+		if err := secret.Validate(); err != nil {
+			return errors.Wrapf(err, "header %s", name)
+		}
+	}
+
+	if d.BasicAuth != nil {
+		if d.BasicAuth.Username == (Secret{}) {
+			return ErrBasicAuthUsername
+		}
+		if err := d.BasicAuth.Username.Validate(); err != nil {
+			return errors.Wrap(err, "basic_auth username")
+		}
+		if err := d.BasicAuth.Password.Validate(); err != nil {
+			return errors.Wrap(err, "basic_auth password")
+		}
+	}
+
+	if d.BearerToken != nil {
+		if err := d.BearerToken.Validate(); err != nil {
+			return errors.Wrap(err, "bearer_token")
+		}
+	}
+	return nil
+}
+
+// stringToSecretHookFunc decodes a plain string into a literal Secret.
+func stringToSecretHookFunc() mapstructure.DecodeHookFuncType {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String || to != reflect.TypeOf(Secret{}) {
+			return data, nil
+		}
+		return Secret{Value: data.(string)}, nil //nolint:forcetypeassert
+	}
+}
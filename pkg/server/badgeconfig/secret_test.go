@@ -0,0 +1,88 @@
+package badgeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	t.Parallel()
+
+	secretFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		badge      string
+		header     string
+		loadErr    error
+		headersErr error
+	}{
+		{name: "literal header", badge: "headers: {PRIVATE-TOKEN: token}", header: "token"},
+		{name: "file bearer token", badge: "bearer_token: {file: " + secretFile + "}", header: "Bearer file-token"},
+		{
+			name:       "missing env secret",
+			badge:      "bearer_token: {env: BADGESERV_TEST_UNSET_SECRET}",
+			headersErr: ErrSecretNotSet,
+		},
+		{
+			name:       "missing file secret",
+			badge:      "headers: {PRIVATE-TOKEN: {file: " + secretFile + ".missing}}",
+			headersErr: os.ErrNotExist,
+		},
+		{
+			name:    "conflicting auth",
+			badge:   "bearer_token: token, basic_auth: {username: user}",
+			loadErr: ErrConflictingAuth,
+		},
+		{name: "basic auth without username", badge: "basic_auth: {password: password}", loadErr: ErrBasicAuthUsername},
+		{name: "ambiguous secret", badge: "bearer_token: {value: token, env: HOME}", loadErr: ErrSecretInvalid},
+		{
+			name:    "ambiguous header secret",
+			badge:   "headers: {PRIVATE-TOKEN: {env: HOME, file: " + secretFile + "}}",
+			loadErr: ErrSecretInvalid,
+		},
+		{name: "abstract badges aren't checked", badge: "abstract: true, bearer_token: {value: token, env: HOME}"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			config, err := Load([]byte("predefined_badges:\n  badge: {target: \"https://example.com\"}\n" +
+				"  other: {extends: badge, " + test.badge + "}\n"))
+			if test.loadErr != nil {
+				if !errors.Is(err, test.loadErr) {
+					t.Fatalf("Load error = %v, want %v", err, test.loadErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			badge, found := config.PredefinedBadges["other"]
+			if !found {
+				return
+			}
+
+			headers, err := badge.RequestHeaders()
+			if test.headersErr != nil {
+				if !errors.Is(err, test.headersErr) {
+					t.Fatalf("RequestHeaders error = %v, want %v", err, test.headersErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RequestHeaders: %v", err)
+			}
+			if got := headers.Get("Private-Token") + headers.Get("Authorization"); got != test.header {
+				t.Errorf("RequestHeaders = %q, want %q", got, test.header)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/wrouesnel/badgeserv/pkg/server/badgeconfig"
+	"go.uber.org/zap"
+)
+
+// reloadDebounce is how long changes to the badge config directory must settle before it is reloaded, so
+// a file being written or several files being replaced cause a single reload.
+const reloadDebounce = 500 * time.Millisecond
+
+// predefinedState is the predefined badge configuration and the index page data derived from it. They are
+// replaced together so requests never see the data of one with the other.
+type predefinedState struct {
+	config       *badgeconfig.Config
+	templateData interface{}
+}
+
+// predefinedConfig holds the predefined badge configuration, which is reloaded from the badge config
+// directory while the server runs.
+type predefinedConfig struct {
+	dir    string
+	state  atomic.Value
+	logger *zap.Logger
+}
+
+// newPredefinedConfig loads the badge config directory. Unlike reloads, errors in the initial load are fatal.
+func newPredefinedConfig(badgeConfigDir string) (*predefinedConfig, error) {
+	p := &predefinedConfig{
+		dir:    badgeConfigDir,
+		logger: zap.L().With(zap.String("badge_config_dir", badgeConfigDir)),
+	}
+	config, err := loadBadgeConfig(badgeConfigDir)
+	if err != nil {
+		return nil, err
+	}
+	p.set(config)
+	return p, nil
+}
+
+func (p *predefinedConfig) set(config *badgeconfig.Config) {
+	p.state.Store(&predefinedState{
+		config:       config,
+		templateData: getPredefinedBadgesTemplateData(config),
+	})
+}
+
+func (p *predefinedConfig) load() *predefinedState {
+	return p.state.Load().(*predefinedState) //nolint:forcetypeassert
+}
+
+// Config returns the current predefined badge configuration.
+func (p *predefinedConfig) Config() *badgeconfig.Config {
+	return p.load().config
+}
+
+// TemplateData returns the index page data for the current predefined badge configuration.
+func (p *predefinedConfig) TemplateData() interface{} {
+	return p.load().templateData
+}
+
+// Reload loads the badge config directory again. If it fails the previous configuration is kept.
+func (p *predefinedConfig) Reload() error {
+	config, err := loadBadgeConfig(p.dir)
+	if err != nil {
+		p.logger.Error("Predefined badge configuration could not be reloaded, keeping the previous configuration",
+			zap.Error(err))
+		return err
+	}
+	p.set(config)
+	p.logger.Info("Predefined badge configuration reloaded", zap.Int("predefined_badges", len(config.PredefinedBadges)))
+	return nil
+}
+
+// watchDirs adds the badge config directory and its subdirectories to watcher. fsnotify doesn't watch
+// subdirectories itself.
+func (p *predefinedConfig) watchDirs(watcher *fsnotify.Watcher) error {
+	dirs, _, err := badgeconfig.ScanDir(p.dir)
+	if err != nil {
+		return errors.Wrap(err, "watchDirs")
+	}
+	for _, dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return errors.Wrap(err, "watchDirs")
+		}
+	}
+	return nil
+}
+
+// Watch reloads the configuration when the badge config directory or its subdirectories change, or the
+// process receives SIGHUP, until the process exits. If the directory can't be watched an error is
+// returned, but SIGHUP still reloads.
+func (p *predefinedConfig) Watch() error {
+	if p.dir == "" {
+		return nil
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	// Receiving from nil channels blocks, so SIGHUP keeps working if the watcher fails.
+	var (
+		events      <-chan fsnotify.Event
+		watchErrors <-chan error
+	)
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = p.watchDirs(watcher); err != nil {
+			_ = watcher.Close()
+		} else {
+			events, watchErrors = watcher.Events, watcher.Errors
+		}
+	}
+
+	go func() {
+		debounce := time.NewTimer(reloadDebounce)
+		debounce.Stop()
+
+		for {
+			select {
+			case event := <-events:
+				p.logger.Debug("Badge config directory changed", zap.String("event", event.String()))
+				debounce.Reset(reloadDebounce)
+			case err := <-watchErrors:
+				p.logger.Warn("Error watching badge config directory", zap.Error(err))
+			case <-hangup:
+				p.logger.Info("SIGHUP received, reloading predefined badge configuration")
+				_ = p.Reload()
+			case <-debounce.C:
+				// Subdirectories may have been added.
+				if err := p.watchDirs(watcher); err != nil {
+					p.logger.Warn("Error watching badge config directory", zap.Error(err))
+				}
+				_ = p.Reload()
+			}
+		}
+	}()
+
+	if err != nil {
+		return errors.Wrap(err, "Watch")
+	}
+	p.logger.Info("Watching badge config directory for changes")
+	return nil
+}
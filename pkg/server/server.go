@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net/http"
 	"net/url"
 	"sort"
 	"strings"
@@ -19,8 +20,11 @@ import (
 	"github.com/wrouesnel/badgeserv/api/v1"
 	"github.com/wrouesnel/badgeserv/assets"
 	"github.com/wrouesnel/badgeserv/pkg/badges"
+	"github.com/wrouesnel/badgeserv/pkg/cache"
+	"github.com/wrouesnel/badgeserv/pkg/netfilter"
 	"github.com/wrouesnel/badgeserv/pkg/pongorenderer"
 	"github.com/wrouesnel/badgeserv/pkg/server/badgeconfig"
+	"github.com/wrouesnel/badgeserv/pkg/store"
 	"github.com/wrouesnel/badgeserv/version"
 	"go.uber.org/zap"
 	"go.withmatt.com/httpheaders"
@@ -33,6 +37,16 @@ type APIServerConfig struct {
 	Port   int    `help:"Port to serve on" default:"8080"`
 
 	HTTPClient APIHTTPClientConfig `embed:"" prefix:"http"`
+	Cache      cache.Config        `embed:"" prefix:"cache."`
+	Stale      api.StaleConfig     `embed:"" prefix:"stale."`
+	Push       api.PushConfig      `embed:"" prefix:"push."`
+	Coverage   api.CoverageConfig  `embed:"" prefix:"coverage."`
+	Tests      api.TestsConfig     `embed:"" prefix:"tests."`
+
+	TargetFilter netfilter.Config `embed:"" prefix:"target-filter."`
+
+	ErrorBadges           bool `help:"Render failures as error badges unless requested otherwise" default:"false"`
+	DisableDynamicTargets bool `help:"Reject dynamic badges with arbitrary targets so only predefined badges fetch" default:"false"`
 }
 
 // APIHTTPClientConfig configures the outbound HTTP request globals.
@@ -45,6 +59,18 @@ var (
 	ErrAPIInitializationFailed = errors.New("API failed to initialize")
 )
 
+// newHTTPClient returns a client for badge target requests.
+func newHTTPClient(config APIHTTPClientConfig) *resty.Client {
+	client := resty.New()
+	if config.UserAgent == "" {
+		client.SetHeader(httpheaders.UserAgent, fmt.Sprintf("%s/%s", version.Name, version.Version))
+	} else {
+		client.SetHeader(httpheaders.UserAgent, config.UserAgent)
+	}
+	client.SetTimeout(config.Timeout)
+	return client
+}
+
 func loadBadgeConfig(badgeConfigDir string) (*badgeconfig.Config, error) {
 	logger := zap.L()
 	var predefinedBadgeConfig *badgeconfig.Config
@@ -53,7 +79,7 @@ func loadBadgeConfig(badgeConfigDir string) (*badgeconfig.Config, error) {
 		var err error
 		predefinedBadgeConfig, err = badgeconfig.LoadDir(badgeConfigDir)
 		if err != nil {
-			logger.Error("Fatal error loading predefined badge configuration")
+			logger.Error("Error loading predefined badge configuration")
 			return predefinedBadgeConfig, errors.Wrap(err, "badgeconfig")
 		}
 	} else {
@@ -72,6 +98,7 @@ func getPredefinedBadgesTemplateData(predefinedBadgeConfig *badgeconfig.Config)
 	type templateParameter struct {
 		Name        string
 		Description string
+		Required    bool
 	}
 
 	type templatePredefinedBadge struct {
@@ -89,11 +116,7 @@ func getPredefinedBadgesTemplateData(predefinedBadgeConfig *badgeconfig.Config)
 		exampleDefs := []templatePredefinedExample{}
 		for _, example := range predefinedDesc.Examples {
 			exampleURL := url.URL{Path: fmt.Sprintf("badge/predefined/%s/", predefinedName)}
-			qry := exampleURL.Query()
-			for k, v := range example.Parameters {
-				qry.Set(k, v)
-			}
-			exampleURL.RawQuery = qry.Encode()
+			exampleURL.RawQuery = example.Query().Encode()
 
 			exampleDefs = append(exampleDefs, templatePredefinedExample{
 				Description: example.Description,
@@ -101,10 +124,11 @@ func getPredefinedBadgesTemplateData(predefinedBadgeConfig *badgeconfig.Config)
 			})
 		}
 
-		parameterList := lo.MapToSlice(predefinedDesc.Parameters, func(k string, v string) templateParameter {
+		parameterList := lo.MapToSlice(predefinedDesc.Parameters, func(k string, v badgeconfig.Parameter) templateParameter {
 			return templateParameter{
 				Name:        k,
-				Description: v,
+				Description: v.Description,
+				Required:    v.Required,
 			}
 		})
 
@@ -130,28 +154,74 @@ func getPredefinedBadgesTemplateData(predefinedBadgeConfig *badgeconfig.Config)
 func API(serverConfig APIServerConfig, badgeConfig badges.BadgeConfig, assetConfig assets.Config, badgeConfigDir string) error {
 	logger := zap.L()
 
-	predefinedBadgeConfig, err := loadBadgeConfig(badgeConfigDir)
+	predefinedBadges, err := newPredefinedConfig(badgeConfigDir)
 	if err != nil {
 		return errors.Wrap(err, "API")
 	}
+	if err := predefinedBadges.Watch(); err != nil {
+		// The configuration can still be reloaded with SIGHUP.
+		logger.Warn("Badge config directory can't be watched for changes", zap.Error(err))
+	}
 
 	logger.Debug("Configuring API REST client")
-	httpClient := resty.New()
-	if serverConfig.HTTPClient.UserAgent == "" {
-		httpClient.SetHeader(httpheaders.UserAgent, fmt.Sprintf("%s/%s", version.Name, version.Version))
-	} else {
-		httpClient.SetHeader(httpheaders.UserAgent, serverConfig.HTTPClient.UserAgent)
+	targetFilter, err := netfilter.New(serverConfig.TargetFilter)
+	if err != nil {
+		return errors.Wrap(err, "API")
 	}
-	httpClient.SetTimeout(serverConfig.HTTPClient.Timeout)
+
+	// The filter dials the addresses it checked, so the checks apply after DNS resolution and to redirects.
+	httpClient := newHTTPClient(serverConfig.HTTPClient)
+	httpClient.SetTransport(targetFilter.Transport(http.DefaultTransport.(*http.Transport).Clone())) //nolint:forcetypeassert
+	httpClient.SetRedirectPolicy(api.TargetRedirectPolicy())
+
+	// Operator configured targets bypass the filter, so they use their own transport and connections to internal
+	// services are never reused for client supplied targets. Their redirects to other hosts are still filtered.
+	trustedClient := newHTTPClient(serverConfig.HTTPClient)
+	trustedClient.SetTransport(http.DefaultTransport.(*http.Transport).Clone()) //nolint:forcetypeassert
+	trustedClient.SetRedirectPolicy(api.TargetRedirectPolicy(), resty.RedirectPolicyFunc(targetFilter.CheckRedirect))
 	logger.Info("HTTP client initialized", zap.Bool("proxy_set", httpClient.IsProxySet()))
 
 	badgeService := badges.NewBadgeService(&badgeConfig)
 
+	logger.Debug("Configuring response cache", zap.Duration("ttl", serverConfig.Cache.TTL), zap.Int("max_entries", serverConfig.Cache.MaxEntries))
+	responseCache, err := cache.New[interface{}](serverConfig.Cache)
+	if err != nil {
+		return errors.Wrap(err, "API")
+	}
+
+	pushStore, err := store.New(serverConfig.Push.Store)
+	if err != nil {
+		return errors.Wrap(err, "API")
+	}
+	// Uploaded reports are kept alongside pushed values, in stores of their own so they can't collide with
+	// pushed names.
+	coverageStore, err := store.New(serverConfig.Push.Store.Sub("_coverage"))
+	if err != nil {
+		return errors.Wrap(err, "API")
+	}
+	testsStore, err := store.New(serverConfig.Push.Store.Sub("_tests"))
+	if err != nil {
+		return errors.Wrap(err, "API")
+	}
+	logger.Info("Push API configured", zap.Int("namespaces", len(serverConfig.Push.Tokens)),
+		zap.String("store_dir", serverConfig.Push.Store.Dir))
+
 	logger.Debug("Creating API config")
 	apiConfig := &api.Config{
-		BadgeService:     badgeService,
-		HTTPClient:       httpClient,
-		PredefinedBadges: predefinedBadgeConfig,
+		BadgeService:          badgeService,
+		HTTPClient:            httpClient,
+		TrustedClient:         trustedClient,
+		ResponseCache:         responseCache,
+		Stale:                 serverConfig.Stale,
+		ErrorBadges:           serverConfig.ErrorBadges,
+		DisableDynamicTargets: serverConfig.DisableDynamicTargets,
+		PredefinedBadges:      predefinedBadges.Config,
+		Push:                  serverConfig.Push,
+		PushStore:             pushStore,
+		Coverage:              serverConfig.Coverage,
+		CoverageStore:         coverageStore,
+		Tests:                 serverConfig.Tests,
+		TestsStore:            testsStore,
 	}
 	apiInstance, apiPrefix := api.NewAPI(apiConfig)
 
@@ -169,10 +239,12 @@ func API(serverConfig APIServerConfig, badgeConfig badges.BadgeConfig, assetConf
 		"Description": version.Description,
 	}
 	templateGlobals["Colors"] = badgeService.Colors
-	templateGlobals["PredefinedBadges"] = getPredefinedBadgesTemplateData(predefinedBadgeConfig)
+	templateGlobals["Styles"] = badgeService.Styles
+	templateGlobals["Logos"] = badgeService.Logos
+	templateGlobals["PredefinedBadges"] = predefinedBadges.TemplateData
 
 	logger.Info("Starting API server")
-	if err := Server(serverConfig, assetConfig, templateGlobals, APIConfigure(serverConfig, apiInstance, apiPrefix)); err != nil {
+	if err := Server(serverConfig, assetConfig, templateGlobals, responseCache.Metrics(), APIConfigure(serverConfig, apiInstance, apiPrefix)); err != nil {
 		logger.Error("Error from server", zap.Error(err))
 		return errors.Wrap(err, "Server exiting with error")
 	}
@@ -193,6 +265,13 @@ func APIConfigure[T api.ServerInterface](serverConfig APIServerConfig, apiInstan
 			zap.String("api_basepath", fullAPIPrefix))
 
 		api.RegisterHandlersWithBaseURL(e, apiInstance, fullAPIPrefix)
+		// Badge paths can end in .png to request PNG badges, so they must be rewritten before routing.
+		e.Pre(api.PNGSuffixMiddleware(fmt.Sprintf("%s/badge", fullAPIPrefix)))
+		e.Pre(api.StoreTrailingSlashMiddleware(fmt.Sprintf("%s/badge", fullAPIPrefix)))
+		// shields.io compatible routes are served outside the API, so existing badge URLs only need a new host.
+		if shields, ok := any(apiInstance).(api.ShieldsInterface); ok {
+			api.RegisterShieldsHandlers(e, shields, serverConfig.Prefix)
+		}
 		// Add the Swagger API as the frontend.
 		uiPrefix := fmt.Sprintf("%s/ui", fullAPIPrefix)
 		uiHandler := EchoSwaggerUIHandler(uiPrefix, api.OpenAPISpec)
@@ -205,7 +284,8 @@ func APIConfigure[T api.ServerInterface](serverConfig APIServerConfig, apiInstan
 }
 
 // Server configures and starts an Echo server with standard capabilities, and configuration functions.
-func Server(serverConfig APIServerConfig, assetConfig assets.Config, templateGlobals pongo2.Context, configFns ...func(e *echo.Echo) error) error {
+// customMetrics are registered with the Prometheus middleware alongside the standard request metrics.
+func Server(serverConfig APIServerConfig, assetConfig assets.Config, templateGlobals pongo2.Context, customMetrics []*prometheus.Metric, configFns ...func(e *echo.Echo) error) error {
 	logger := zap.L().With(zap.String("subsystem", "server"))
 
 	e := echo.New()
@@ -220,7 +300,7 @@ func Server(serverConfig APIServerConfig, assetConfig assets.Config, templateGlo
 	e.Renderer = pongorenderer.NewRenderer(webTemplateSet)
 
 	// Setup Prometheus monitoring
-	p := prometheus.NewPrometheus(version.Name, nil)
+	p := prometheus.NewPrometheus(version.Name, nil, customMetrics)
 	p.Use(e)
 
 	// Setup logging
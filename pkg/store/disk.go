@@ -0,0 +1,91 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	diskDirMode    = 0o750
+	diskFileSuffix = ".json"
+)
+
+// Disk is a Store which keeps each value in a file under <dir>/<namespace>/<name>.json, so values
+// survive restarts.
+type Disk struct {
+	dir string
+}
+
+// NewDisk initializes a Disk store in dir, creating it if necessary.
+func NewDisk(dir string) (*Disk, error) {
+	if err := os.MkdirAll(dir, diskDirMode); err != nil {
+		return nil, errors.Wrap(err, "NewDisk")
+	}
+	return &Disk{dir: dir}, nil
+}
+
+func (d *Disk) path(namespace string, name string) string {
+	return filepath.Join(d.dir, namespace, name+diskFileSuffix)
+}
+
+// Get implements Store.
+func (d *Disk) Get(namespace string, name string) (Record, error) {
+	if err := ValidateKey(namespace, name); err != nil {
+		return Record{}, err
+	}
+
+	data, err := os.ReadFile(d.path(namespace, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return Record{}, ErrNotFound
+	}
+	if err != nil {
+		return Record{}, errors.Wrap(err, "Disk.Get")
+	}
+
+	record := Record{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, errors.Wrapf(err, "Disk.Get: %s", d.path(namespace, name))
+	}
+	return record, nil
+}
+
+// Put implements Store. Values are written to a temporary file which replaces the old value, so
+// readers never see a partly written value.
+func (d *Disk) Put(namespace string, name string, record Record) error {
+	if err := ValidateKey(namespace, name); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&record)
+	if err != nil {
+		return errors.Wrap(err, "Disk.Put")
+	}
+
+	namespaceDir := filepath.Join(d.dir, namespace)
+	if err := os.MkdirAll(namespaceDir, diskDirMode); err != nil {
+		return errors.Wrap(err, "Disk.Put")
+	}
+
+	tmp, err := os.CreateTemp(namespaceDir, name+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "Disk.Put")
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "Disk.Put")
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "Disk.Put")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "Disk.Put")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), d.path(namespace, name)), "Disk.Put")
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"sync"
+)
+
+// Memory is a Store which keeps values in memory. Values are lost when the server exits.
+type Memory struct {
+	records map[string]Record
+	mtx     sync.RWMutex
+}
+
+// NewMemory initializes an empty Memory store.
+func NewMemory() *Memory {
+	return &Memory{records: map[string]Record{}}
+}
+
+func memoryKey(namespace string, name string) string {
+	return namespace + "/" + name
+}
+
+// Get implements Store.
+func (m *Memory) Get(namespace string, name string) (Record, error) {
+	if err := ValidateKey(namespace, name); err != nil {
+		return Record{}, err
+	}
+
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	record, ok := m.records[memoryKey(namespace, name)]
+	if !ok {
+		return Record{}, ErrNotFound
+	}
+	return record, nil
+}
+
+// Put implements Store.
+func (m *Memory) Put(namespace string, name string, record Record) error {
+	if err := ValidateKey(namespace, name); err != nil {
+		return err
+	}
+	// The caller keeps its slice, so the data is copied.
+	record.Data = append([]byte(nil), record.Data...)
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.records[memoryKey(namespace, name)] = record
+	return nil
+}
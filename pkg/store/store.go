@@ -0,0 +1,86 @@
+// Package store implements storage for badge values pushed to the API.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNotFound   = errors.New("pushed value not found")
+	ErrInvalidKey = errors.New("namespaces and names must start with a letter or digit and only contain letters, digits, '.', '_' and '-'")
+)
+
+// keyRegex matches valid namespaces and names. They are used as file names by the disk store.
+var keyRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`) //nolint:gochecknoglobals
+
+// Config configures the store for pushed values.
+type Config struct {
+	Dir string `help:"Directory pushed badge values are stored in. Values are kept in memory and lost on restart if not set" default:""`
+}
+
+// Sub returns the configuration of a store kept separately from this one. Keys can't start with an
+// underscore, so names starting with one can't collide with a namespace.
+func (c Config) Sub(name string) Config {
+	if c.Dir == "" {
+		return c
+	}
+	return Config{Dir: filepath.Join(c.Dir, name)}
+}
+
+// Record is a value pushed to the store.
+type Record struct {
+	// Data is the pushed JSON document.
+	Data json.RawMessage `json:"data"`
+	// Updated is when the value was pushed.
+	Updated time.Time `json:"updated"`
+}
+
+// Store keeps the latest value pushed to each name in a namespace.
+type Store interface {
+	// Get returns the value of a name, or ErrNotFound.
+	Get(namespace string, name string) (Record, error)
+	// Put replaces the value of a name.
+	Put(namespace string, name string, record Record) error
+}
+
+// New initializes the store selected by the configuration.
+func New(config Config) (Store, error) {
+	if config.Dir == "" {
+		return NewMemory(), nil
+	}
+	return NewDisk(config.Dir)
+}
+
+// ValidateKey returns ErrInvalidKey if namespace or name can't be stored.
+func ValidateKey(namespace string, name string) error {
+	for _, key := range []string{namespace, name} {
+		if !keyRegex.MatchString(key) {
+			return errors.Wrapf(ErrInvalidKey, "%q", key)
+		}
+	}
+	return nil
+}
+
+// EscapeKey converts an arbitrary string, such as a branch name, into a key. Characters which aren't
+// allowed in keys are written as _xx hex escapes, so different strings never share a key. The key is
+// still invalid if value doesn't start with a letter or digit.
+func EscapeKey(value string) string {
+	key := strings.Builder{}
+	for idx := 0; idx < len(value); idx++ {
+		char := value[idx]
+		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') ||
+			(idx > 0 && (char == '.' || char == '-')) {
+			key.WriteByte(char)
+		} else {
+			key.WriteString(fmt.Sprintf("_%02x", char))
+		}
+	}
+	return key.String()
+}
@@ -0,0 +1,229 @@
+// Package testresults summarizes the test reports of common test tools.
+package testresults
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown test report format")
+	ErrParseFailed   = errors.New("test report could not be parsed")
+	ErrNoTests       = errors.New("test report has no tests")
+)
+
+// Format is a supported test report format.
+type Format string
+
+const (
+	// Auto selects the format from the content of the report.
+	Auto  Format = "auto"
+	JUnit Format = "junit"
+	// GoJSON is the event stream written by go test -json.
+	GoJSON Format = "go"
+)
+
+// Formats lists the accepted format names.
+var Formats = []Format{Auto, JUnit, GoJSON} //nolint:gochecknoglobals
+
+// Parse returns the Format with the given name. An empty name is Auto.
+func Parse(name string) (Format, error) {
+	if name == "" {
+		return Auto, nil
+	}
+	format := Format(strings.ToLower(name))
+	if !lo.Contains(Formats, format) {
+		return "", errors.Wrapf(ErrUnknownFormat, "%q", name)
+	}
+	return format, nil
+}
+
+// Summary counts the results of the tests in a report.
+type Summary struct {
+	Passed  int64
+	Failed  int64
+	Skipped int64
+}
+
+// Total returns the number of tests in the report.
+func (s Summary) Total() int64 {
+	return s.Passed + s.Failed + s.Skipped
+}
+
+// Detect returns the format of a report from its content.
+func Detect(data []byte) (Format, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return JUnit, nil
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return GoJSON, nil
+	default:
+		return "", errors.Wrap(ErrUnknownFormat, "report format could not be detected")
+	}
+}
+
+// Compute returns the summary of a report. Auto detects the format of the report.
+func Compute(format Format, data []byte) (Summary, error) {
+	var err error
+	if format == Auto {
+		if format, err = Detect(data); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	var summary Summary
+	switch format {
+	case JUnit:
+		summary, err = parseJUnit(data)
+	case GoJSON:
+		summary, err = parseGoJSON(data)
+	case Auto:
+		return Summary{}, errors.Wrap(ErrUnknownFormat, "Compute: BUG - auto format was not resolved")
+	default:
+		return Summary{}, errors.Wrapf(ErrUnknownFormat, "%q", format)
+	}
+
+	if err != nil {
+		return Summary{}, errors.Wrapf(ErrParseFailed, "%s: %s", format, err.Error())
+	}
+	if summary.Total() == 0 {
+		return Summary{}, errors.Wrapf(ErrNoTests, "%s", format)
+	}
+	return summary, nil
+}
+
+// parseJUnit parses a JUnit XML report. Test cases with a failure or error element failed, those with
+// a skipped element were skipped, and the rest passed. Reports can be large, so they are streamed.
+func parseJUnit(data []byte) (Summary, error) {
+	summary := Summary{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	inTestCase, failed, skipped := false, false, false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return summary, nil
+		}
+		if err != nil {
+			return Summary{}, errors.Wrap(err, "XML report")
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "testcase":
+				inTestCase, failed, skipped = true, false, false
+			case "failure", "error":
+				failed = failed || inTestCase
+			case "skipped":
+				skipped = skipped || inTestCase
+			}
+		case xml.EndElement:
+			if element.Name.Local != "testcase" {
+				continue
+			}
+			switch {
+			case failed:
+				summary.Failed++
+			case skipped:
+				summary.Skipped++
+			default:
+				summary.Passed++
+			}
+			inTestCase = false
+		}
+	}
+}
+
+// goTestEvent is an event of the go test -json stream.
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+}
+
+// goPackage is the state of a package in the go test -json stream.
+type goPackage struct {
+	// result is the last package level pass, fail or skip action.
+	result    string
+	hasOutput bool
+	// failedTests is the number of tests of the package which failed.
+	failedTests int
+}
+
+// parseGoJSON parses the output of go test -json. Subtests are counted as tests, as go test reports them,
+// and a test run more than once is counted by its last result. A package which failed without a failing
+// test, such as one which failed to build or crashed, or which wrote output but never finished, is counted
+// as one failed test. Lines which aren't events are ignored.
+func parseGoJSON(data []byte) (Summary, error) {
+	results := map[string]string{}
+	packages := map[string]*goPackage{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, bufio.MaxScanTokenSize*16) //nolint:gomnd
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+
+		event := goTestEvent{}
+		if err := json.Unmarshal(line, &event); err != nil {
+			return Summary{}, errors.Wrapf(err, "line %d", lineNum)
+		}
+		if event.Package == "" {
+			continue
+		}
+		pkg := packages[event.Package]
+		if pkg == nil {
+			pkg = &goPackage{}
+			packages[event.Package] = pkg
+		}
+		if event.Action == "output" {
+			pkg.hasOutput = true
+		}
+		if !lo.Contains([]string{"pass", "fail", "skip"}, event.Action) {
+			continue
+		}
+		if event.Test == "" {
+			pkg.result = event.Action
+			continue
+		}
+		results[event.Package+"\x00"+event.Test] = event.Action
+	}
+	if err := scanner.Err(); err != nil {
+		return Summary{}, errors.Wrap(err, "parseGoJSON")
+	}
+
+	summary := Summary{}
+	for key, action := range results {
+		switch action {
+		case "pass":
+			summary.Passed++
+		case "fail":
+			summary.Failed++
+			packageName, _, _ := strings.Cut(key, "\x00")
+			packages[packageName].failedTests++
+		case "skip":
+			summary.Skipped++
+		}
+	}
+	for _, pkg := range packages {
+		unfinished := pkg.result == "" && pkg.hasOutput
+		if (pkg.result == "fail" || unfinished) && pkg.failedTests == 0 {
+			summary.Failed++
+		}
+	}
+	return summary, nil
+}
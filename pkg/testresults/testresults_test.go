@@ -0,0 +1,120 @@
+package testresults
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format Format
+		report string
+		want   Summary
+		err    error
+	}{
+		{
+			name:   "go json",
+			format: GoJSON,
+			report: strings.Join([]string{
+				`{"Action":"run","Package":"p","Test":"TestA"}`,
+				`{"Action":"pass","Package":"p","Test":"TestA"}`,
+				`{"Action":"fail","Package":"p","Test":"TestB"}`,
+				`{"Action":"pass","Package":"p","Test":"TestB/sub"}`,
+				`{"Action":"skip","Package":"p","Test":"TestC"}`,
+				`{"Action":"fail","Package":"p"}`,
+			}, "\n"),
+			want: Summary{Passed: 2, Failed: 1, Skipped: 1},
+		},
+		{
+			name:   "go json rerun counts last result",
+			format: GoJSON,
+			report: strings.Join([]string{
+				`{"Action":"fail","Package":"p","Test":"TestA"}`,
+				`{"Action":"pass","Package":"p","Test":"TestA"}`,
+				`{"Action":"pass","Package":"p"}`,
+			}, "\n"),
+			want: Summary{Passed: 1},
+		},
+		{
+			name:   "go json build failure",
+			format: GoJSON,
+			report: strings.Join([]string{
+				`{"ImportPath":"p/bad [p/bad.test]","Action":"build-output","Output":"undefined: x\n"}`,
+				`{"ImportPath":"p/bad [p/bad.test]","Action":"build-fail"}`,
+				`{"Action":"start","Package":"p/bad"}`,
+				`{"Action":"fail","Package":"p/bad","FailedBuild":"p/bad [p/bad.test]"}`,
+				`{"Action":"pass","Package":"p/ok","Test":"TestA"}`,
+				`{"Action":"pass","Package":"p/ok"}`,
+			}, "\n"),
+			want: Summary{Passed: 1, Failed: 1},
+		},
+		{
+			name:   "go json build failure as output",
+			format: GoJSON,
+			report: "# p/bad\nbad.go:1: undefined: x\n" +
+				`{"Action":"output","Package":"p/bad","Output":"FAIL\tp/bad [build failed]\n"}` + "\n" +
+				`{"Action":"fail","Package":"p/bad"}`,
+			want: Summary{Failed: 1},
+		},
+		{
+			name:   "go json unfinished package",
+			format: GoJSON,
+			report: strings.Join([]string{
+				`{"Action":"pass","Package":"p","Test":"TestA"}`,
+				`{"Action":"output","Package":"p","Output":"panic: test timed out\n"}`,
+			}, "\n"),
+			want: Summary{Passed: 1, Failed: 1},
+		},
+		{
+			name:   "go json no tests",
+			format: GoJSON,
+			report: `{"Action":"output","Package":"p","Output":"?   \tp\t[no test files]\n"}` + "\n" +
+				`{"Action":"skip","Package":"p"}`,
+			err: ErrNoTests,
+		},
+		{
+			name:   "junit",
+			format: JUnit,
+			report: `<testsuites><testsuite><testcase name="a"/><testcase name="b"><failure/></testcase>` +
+				`<testcase name="c"><error/></testcase><testcase name="d"><skipped/></testcase></testsuite></testsuites>`,
+			want: Summary{Passed: 1, Failed: 2, Skipped: 1},
+		},
+		{
+			name:   "auto detects junit",
+			format: Auto,
+			report: `<?xml version="1.0"?><testsuite><testcase name="a"></testcase></testsuite>`,
+			want:   Summary{Passed: 1},
+		},
+		{
+			name:   "invalid go json",
+			format: GoJSON,
+			report: `{"Action":`,
+			err:    ErrParseFailed,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			summary, err := Compute(test.format, []byte(test.report))
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Fatalf("Compute error = %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Compute: %v", err)
+			}
+			if summary != test.want {
+				t.Errorf("Compute = %+v, want %+v", summary, test.want)
+			}
+		})
+	}
+}